@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"proyecto/internal/authstore"
+)
+
+// handleLogin atiende el formulario de inicio de sesión (GET) y su envío
+// (POST). Una vez validada la contraseña, emite la cookie de sesión
+// firmada (ver internal/session).
+func handleLogin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		templates.ExecuteTemplate(w, "formulario_login.html", nil)
+	case http.MethodPost:
+		username := r.FormValue("username")
+		password := r.FormValue("password")
+
+		u, err := users.GetByUsername(r.Context(), username)
+		if errors.Is(err, authstore.ErrNotFound) {
+			http.Error(w, "usuario o contraseña inválidos", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+			http.Error(w, "usuario o contraseña inválidos", http.StatusUnauthorized)
+			return
+		}
+
+		sessionMgr.SetCookie(w, u.Username)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	default:
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleLogout borra la cookie de sesión.
+func handleLogout(w http.ResponseWriter, r *http.Request) {
+	sessionMgr.ClearCookie(w)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// handleRegister crea una cuenta de operador nueva. Las cuentas sólo
+// gatean el acceso (login); el token de API sigue siendo el global
+// configurado por el operador (providers.yaml o token.txt), compartido
+// por todas las cuentas — ver internal/authstore.User.
+func handleRegister(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		templates.ExecuteTemplate(w, "formulario_registro.html", nil)
+	case http.MethodPost:
+		username := r.FormValue("username")
+		password := r.FormValue("password")
+		if username == "" || password == "" {
+			http.Error(w, "usuario y contraseña son obligatorios", http.StatusBadRequest)
+			return
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			http.Error(w, "generando hash de contraseña: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if _, err := users.CreateUser(r.Context(), username, string(hash)); errors.Is(err, authstore.ErrUserExists) {
+			http.Error(w, "el usuario ya existe", http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sessionMgr.SetCookie(w, username)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	default:
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+	}
+}
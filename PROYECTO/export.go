@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+
+	"proyecto/internal/jobstore"
+)
+
+// exportRows serializa las filas de un job en el formato pedido. xlsx
+// delega en excelize; csv y json se arman a mano porque no tienen
+// complejidad suficiente para justificar una dependencia extra.
+func exportRows(format string, rows []jobstore.Row) (data []byte, contentType string, err error) {
+	switch format {
+	case "", "json":
+		data, err = json.MarshalIndent(rows, "", "  ")
+		return data, "application/json; charset=utf-8", err
+	case "csv":
+		data, err = exportRowsCSV(rows)
+		return data, "text/csv; charset=utf-8", err
+	case "xlsx":
+		data, err = exportRowsXLSX(rows)
+		return data, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", err
+	default:
+		return nil, "", fmt.Errorf("formato de exportación no soportado: %q", format)
+	}
+}
+
+func exportRowsCSV(rows []jobstore.Row) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"dni", "nombre_input", "paterno_input", "materno_input",
+		"nombre_api", "paterno_api", "materno_api", "status", "match_score", "match_level", "error_message"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, r := range rows {
+		record := []string{r.DNI, r.NombreInput, r.PaternoInput, r.MaternoInput,
+			r.NombreAPI, r.PaternoAPI, r.MaternoAPI, r.Status,
+			strconv.FormatFloat(r.MatchScore, 'f', 4, 64), r.MatchLevel, r.ErrorMessage}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// exportRowsXLSX genera un .xlsx con una hoja "Resultados" usando
+// excelize.
+func exportRowsXLSX(rows []jobstore.Row) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Resultados"
+	f.SetSheetName(f.GetSheetName(0), sheet)
+
+	header := []string{"dni", "nombre_input", "paterno_input", "materno_input",
+		"nombre_api", "paterno_api", "materno_api", "status", "match_score", "match_level", "error_message"}
+	for col, h := range header {
+		cell, err := excelize.CoordinatesToCellName(col+1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetCellValue(sheet, cell, h); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, r := range rows {
+		row := i + 2 // fila 1 es el header
+		values := []interface{}{r.DNI, r.NombreInput, r.PaternoInput, r.MaternoInput,
+			r.NombreAPI, r.PaternoAPI, r.MaternoAPI, r.Status, r.MatchScore, r.MatchLevel, r.ErrorMessage}
+		for col, v := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, row)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("generando xlsx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
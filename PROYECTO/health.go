@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"proyecto/internal/jobstore"
+)
+
+// handleHealthz es el liveness check: si el proceso responde, está vivo.
+// No toca dependencias externas a propósito.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// handleReadyz es el readiness check: verifica que el store esté abierto,
+// que haya un proveedor DNI configurado y que no esté fallando por
+// completo ahora mismo (providers.yaml con credenciales o endpoints
+// rotos, o rate limiting sostenido), antes de aceptar tráfico real, para
+// poder vivir detrás de un balanceador de verdad.
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if dniProvider == nil {
+		http.Error(w, "sin proveedor DNI configurado", http.StatusServiceUnavailable)
+		return
+	}
+	if statter, ok := dniProvider.(interface{ AllProvidersFailing() bool }); ok && statter.AllProvidersFailing() {
+		http.Error(w, "todos los proveedores DNI están fallando ahora mismo", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	// __readyz__ nunca existe de verdad: sólo nos interesa que la consulta
+	// llegue y vuelva sin error de infraestructura (ErrNotFound es la
+	// respuesta esperada y significa que SQLite está sano).
+	if _, err := store.GetJob(ctx, "__readyz__"); err != nil && !errors.Is(err, jobstore.ErrNotFound) {
+		http.Error(w, "store no disponible: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
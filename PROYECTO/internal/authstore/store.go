@@ -0,0 +1,123 @@
+// Package authstore persiste cuentas de operador en SQLite, siguiendo el
+// mismo esquema de Store que internal/jobstore. No guarda tokens de API
+// por usuario: el token es global (ver User).
+package authstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNotFound se devuelve cuando el usuario solicitado no existe.
+var ErrNotFound = errors.New("authstore: usuario no encontrado")
+
+// ErrUserExists se devuelve al registrar un username ya tomado.
+var ErrUserExists = errors.New("authstore: el usuario ya existe")
+
+// User es una cuenta de operador. PasswordHash es un hash bcrypt.
+//
+// Las cuentas sólo gatean el acceso (login) — no hay token de API por
+// usuario. El token sigue siendo uno global configurado por el operador
+// vía providers.yaml o token.txt (ver setupProvider en el paquete main) y
+// compartido por todas las cuentas; esto es un presupuesto de API
+// compartido, no aislado por usuario.
+type User struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+// Store envuelve la conexión SQLite de usuarios. Es seguro para uso
+// concurrente: database/sql ya serializa el acceso.
+type Store struct {
+	db *sql.DB
+}
+
+// Open abre (o crea) la base de usuarios en path y aplica las migraciones.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("authstore: abriendo %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			username      TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL,
+			created_at    DATETIME NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("authstore: migrando esquema: %w", err)
+	}
+	return nil
+}
+
+// Close cierra la conexión subyacente.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateUser inserta un usuario nuevo. Devuelve ErrUserExists si el
+// username ya está tomado.
+func (s *Store) CreateUser(ctx context.Context, username, passwordHash string) (User, error) {
+	u := User{Username: username, PasswordHash: passwordHash, CreatedAt: time.Now()}
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO users (username, password_hash, created_at) VALUES (?, ?, ?)`,
+		u.Username, u.PasswordHash, u.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return User{}, ErrUserExists
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("authstore: creando usuario: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return User{}, fmt.Errorf("authstore: leyendo id del usuario creado: %w", err)
+	}
+	u.ID = id
+	return u, nil
+}
+
+// GetByUsername busca un usuario por nombre. Devuelve ErrNotFound si no
+// existe.
+func (s *Store) GetByUsername(ctx context.Context, username string) (User, error) {
+	var u User
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, username, password_hash, created_at FROM users WHERE username = ?`, username,
+	).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("authstore: leyendo usuario %q: %w", username, err)
+	}
+	return u, nil
+}
+
+// isUniqueViolation detecta el error de restricción UNIQUE que devuelve
+// modernc.org/sqlite sin depender de su tipo de error interno.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "unique constraint")
+}
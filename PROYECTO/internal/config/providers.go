@@ -0,0 +1,70 @@
+// Package config carga providers.yaml, que reemplaza al antiguo token.txt
+// como fuente de verdad para credenciales y ajustes de cada proveedor DNI.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig describe un proveedor registrado en providers.yaml.
+type ProviderConfig struct {
+	Name    string  `yaml:"name"`
+	Type    string  `yaml:"type"` // decolecta | apisperu | apisnet
+	BaseURL string  `yaml:"base_url"`
+	Token   string  `yaml:"token"`
+	RPS     float64 `yaml:"rps"`
+	Burst   int     `yaml:"burst"`
+}
+
+// CacheConfig controla el cache on-disk de resultados por DNI.
+type CacheConfig struct {
+	Path     string        `yaml:"path"`
+	TTL      time.Duration `yaml:"ttl"`
+	MaxItems int           `yaml:"max_items"`
+}
+
+// Config es la forma completa de providers.yaml.
+type Config struct {
+	Providers []ProviderConfig `yaml:"providers"`
+	Cache     CacheConfig      `yaml:"cache"`
+}
+
+// Load lee y valida providers.yaml. Aplica valores por defecto razonables
+// para que un archivo mínimo (sólo proveedores + tokens) siga funcionando.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("config: leyendo %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("config: parseando %s: %w", path, err)
+	}
+	if len(cfg.Providers) == 0 {
+		return Config{}, fmt.Errorf("config: %s no define ningún proveedor", path)
+	}
+
+	if cfg.Cache.Path == "" {
+		cfg.Cache.Path = "provider_cache.json"
+	}
+	if cfg.Cache.TTL == 0 {
+		cfg.Cache.TTL = 24 * time.Hour
+	}
+	if cfg.Cache.MaxItems == 0 {
+		cfg.Cache.MaxItems = 10000
+	}
+	for i := range cfg.Providers {
+		if cfg.Providers[i].RPS == 0 {
+			cfg.Providers[i].RPS = 5
+		}
+		if cfg.Providers[i].Burst == 0 {
+			cfg.Providers[i].Burst = 10
+		}
+	}
+	return cfg, nil
+}
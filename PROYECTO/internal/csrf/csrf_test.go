@@ -0,0 +1,35 @@
+package csrf
+
+import "testing"
+
+func TestValidAcceptsOwnToken(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	token := m.Token("ana")
+	if !m.Valid("ana", token) {
+		t.Fatal("Valid() = false for a token this Manager just issued")
+	}
+}
+
+func TestValidRejectsEmptyToken(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	if m.Valid("ana", "") {
+		t.Fatal("Valid() = true for an empty token")
+	}
+}
+
+func TestValidRejectsTokenForDifferentUser(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	token := m.Token("ana")
+	if m.Valid("maria", token) {
+		t.Fatal("Valid() = true for a token issued to a different user")
+	}
+}
+
+func TestValidRejectsTokenFromDifferentKey(t *testing.T) {
+	issuer := NewManager([]byte("clave-uno"))
+	verifier := NewManager([]byte("clave-dos"))
+	token := issuer.Token("ana")
+	if verifier.Valid("ana", token) {
+		t.Fatal("Valid() = true for a token signed with a different key")
+	}
+}
@@ -0,0 +1,264 @@
+// Package jobstore persiste jobs de validación masiva de DNIs y sus filas
+// en SQLite, para que un lote sobreviva a un reinicio del proceso y pueda
+// consultarse por la API REST en vez de vivir sólo en la memoria de un
+// request.
+package jobstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNotFound se devuelve cuando un job o fila solicitados no existen.
+var ErrNotFound = errors.New("jobstore: no encontrado")
+
+// Status de un job.
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusDone       = "done"
+)
+
+// Status de una fila dentro de un job.
+const (
+	RowStatusPending  = "pending"
+	RowStatusOK       = "ok"
+	RowStatusMismatch = "mismatch"
+	RowStatusError    = "error"
+)
+
+// Job resume el estado de un lote.
+type Job struct {
+	ID        string
+	Total     int
+	Done      int
+	Status    string
+	CreatedAt time.Time
+}
+
+// Row es una fila (un DNI) dentro de un job.
+type Row struct {
+	ID           int
+	JobID        string
+	DNI          string
+	NombreInput  string
+	PaternoInput string
+	MaternoInput string
+	NombreAPI    string
+	PaternoAPI   string
+	MaternoAPI   string
+	Status       string
+	ErrorMessage string
+	MatchScore   float64
+	MatchLevel   string
+}
+
+// Store envuelve la conexión SQLite y expone las operaciones del modelo de
+// jobs. Es seguro para uso concurrente: database/sql ya serializa el acceso.
+type Store struct {
+	db *sql.DB
+}
+
+// Open abre (o crea) la base SQLite en path y aplica el esquema si hace
+// falta.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: abriendo %s: %w", path, err)
+	}
+	// SQLite no tolera bien muchas conexiones escribiendo en paralelo.
+	db.SetMaxOpenConns(1)
+
+	// modernc.org/sqlite, como el SQLite de referencia, trae el chequeo de
+	// foreign keys apagado por defecto; sin esto el ON DELETE CASCADE de
+	// rows(job_id) no se aplica nunca y DeleteJob deja filas huérfanas.
+	if _, err := db.Exec(`PRAGMA foreign_keys = ON;`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("jobstore: activando foreign_keys: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS jobs (
+			id         TEXT PRIMARY KEY,
+			total      INTEGER NOT NULL,
+			done       INTEGER NOT NULL DEFAULT 0,
+			status     TEXT NOT NULL DEFAULT 'pending',
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS rows (
+			id            INTEGER NOT NULL,
+			job_id        TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+			dni           TEXT NOT NULL,
+			nombre_input  TEXT NOT NULL DEFAULT '',
+			paterno_input TEXT NOT NULL DEFAULT '',
+			materno_input TEXT NOT NULL DEFAULT '',
+			nombre_api    TEXT NOT NULL DEFAULT '',
+			paterno_api   TEXT NOT NULL DEFAULT '',
+			materno_api   TEXT NOT NULL DEFAULT '',
+			status        TEXT NOT NULL DEFAULT 'pending',
+			error_message TEXT NOT NULL DEFAULT '',
+			match_score   REAL NOT NULL DEFAULT 0,
+			match_level   TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (job_id, id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_rows_job_status ON rows(job_id, status);
+	`)
+	if err != nil {
+		return fmt.Errorf("jobstore: migrando esquema: %w", err)
+	}
+	return nil
+}
+
+// Close cierra la conexión subyacente.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateJob inserta un job nuevo junto con sus filas iniciales (estado
+// pending) en una única transacción.
+func (s *Store) CreateJob(ctx context.Context, jobID string, rows []Row) (Job, error) {
+	j := Job{ID: jobID, Total: len(rows), Status: StatusPending, CreatedAt: time.Now()}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Job{}, fmt.Errorf("jobstore: iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO jobs (id, total, done, status, created_at) VALUES (?, ?, 0, ?, ?)`,
+		j.ID, j.Total, j.Status, j.CreatedAt,
+	); err != nil {
+		return Job{}, fmt.Errorf("jobstore: creando job: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO rows (id, job_id, dni, nombre_input, paterno_input, materno_input, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return Job{}, fmt.Errorf("jobstore: preparando inserción de filas: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		if _, err := stmt.ExecContext(ctx, r.ID, j.ID, r.DNI, r.NombreInput, r.PaternoInput, r.MaternoInput, RowStatusPending); err != nil {
+			return Job{}, fmt.Errorf("jobstore: insertando fila %d: %w", r.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Job{}, fmt.Errorf("jobstore: confirmando transacción: %w", err)
+	}
+	return j, nil
+}
+
+// UpdateRow guarda el resultado de una fila ya consultada y avanza el
+// contador `done` del job. Cuando done alcanza total, el job pasa a
+// StatusDone.
+func (s *Store) UpdateRow(ctx context.Context, r Row) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("jobstore: iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE rows SET nombre_api = ?, paterno_api = ?, materno_api = ?, status = ?, error_message = ?,
+			match_score = ?, match_level = ?
+		WHERE job_id = ? AND id = ?
+	`, r.NombreAPI, r.PaternoAPI, r.MaternoAPI, r.Status, r.ErrorMessage, r.MatchScore, r.MatchLevel, r.JobID, r.ID); err != nil {
+		return fmt.Errorf("jobstore: actualizando fila %d: %w", r.ID, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE jobs SET done = done + 1,
+			status = CASE WHEN done + 1 >= total THEN ? ELSE ? END
+		WHERE id = ?
+	`, StatusDone, StatusProcessing, r.JobID)
+	if err != nil {
+		return fmt.Errorf("jobstore: actualizando progreso del job: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetJob devuelve el resumen de un job.
+func (s *Store) GetJob(ctx context.Context, jobID string) (Job, error) {
+	var j Job
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, total, done, status, created_at FROM jobs WHERE id = ?`, jobID,
+	).Scan(&j.ID, &j.Total, &j.Done, &j.Status, &j.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrNotFound
+	}
+	if err != nil {
+		return Job{}, fmt.Errorf("jobstore: leyendo job %s: %w", jobID, err)
+	}
+	return j, nil
+}
+
+// ListRows pagina las filas de un job, opcionalmente filtradas por status
+// ("mismatch", "error", "ok"). limit<=0 significa "sin límite".
+func (s *Store) ListRows(ctx context.Context, jobID, status string, offset, limit int) ([]Row, error) {
+	query := `SELECT id, job_id, dni, nombre_input, paterno_input, materno_input,
+			nombre_api, paterno_api, materno_api, status, error_message, match_score, match_level
+		FROM rows WHERE job_id = ?`
+	args := []interface{}{jobID}
+
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id`
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: listando filas del job %s: %w", jobID, err)
+	}
+	defer rows.Close()
+
+	var out []Row
+	for rows.Next() {
+		var r Row
+		if err := rows.Scan(&r.ID, &r.JobID, &r.DNI, &r.NombreInput, &r.PaternoInput, &r.MaternoInput,
+			&r.NombreAPI, &r.PaternoAPI, &r.MaternoAPI, &r.Status, &r.ErrorMessage, &r.MatchScore, &r.MatchLevel); err != nil {
+			return nil, fmt.Errorf("jobstore: leyendo fila: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// DeleteJob borra un job y, por el ON DELETE CASCADE, todas sus filas.
+func (s *Store) DeleteJob(ctx context.Context, jobID string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = ?`, jobID)
+	if err != nil {
+		return fmt.Errorf("jobstore: borrando job %s: %w", jobID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package jobstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestCreateJobAndGetJob(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	rows := []Row{
+		{ID: 0, DNI: "11111111", NombreInput: "ANA"},
+		{ID: 1, DNI: "22222222", NombreInput: "LUIS"},
+	}
+	j, err := s.CreateJob(ctx, "job-1", rows)
+	if err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if j.Total != 2 || j.Status != StatusPending || j.Done != 0 {
+		t.Fatalf("CreateJob() = %+v, want Total=2 Status=pending Done=0", j)
+	}
+
+	got, err := s.GetJob(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.ID != "job-1" || got.Total != 2 {
+		t.Fatalf("GetJob() = %+v", got)
+	}
+}
+
+func TestGetJobNotFound(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.GetJob(context.Background(), "nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetJob() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateRowAdvancesJobStatus(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	rows := []Row{
+		{ID: 0, DNI: "11111111"},
+		{ID: 1, DNI: "22222222"},
+	}
+	if _, err := s.CreateJob(ctx, "job-1", rows); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if err := s.UpdateRow(ctx, Row{JobID: "job-1", ID: 0, NombreAPI: "ANA", Status: RowStatusOK, MatchScore: 1, MatchLevel: "exact"}); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	j, err := s.GetJob(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if j.Done != 1 || j.Status != StatusProcessing {
+		t.Fatalf("after one of two rows: GetJob() = %+v, want Done=1 Status=processing", j)
+	}
+
+	if err := s.UpdateRow(ctx, Row{JobID: "job-1", ID: 1, Status: RowStatusMismatch}); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	j, err = s.GetJob(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if j.Done != 2 || j.Status != StatusDone {
+		t.Fatalf("after both rows: GetJob() = %+v, want Done=2 Status=done", j)
+	}
+}
+
+func TestUpdateRowUnknownJobReturnsNotFound(t *testing.T) {
+	s := openTestStore(t)
+	err := s.UpdateRow(context.Background(), Row{JobID: "nope", ID: 0, Status: RowStatusOK})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("UpdateRow() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListRowsFiltersAndPaginates(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	rows := make([]Row, 5)
+	for i := range rows {
+		rows[i] = Row{ID: i, DNI: "1111111" + string(rune('0'+i))}
+	}
+	if _, err := s.CreateJob(ctx, "job-1", rows); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	for i := range rows {
+		status := RowStatusOK
+		if i%2 == 0 {
+			status = RowStatusMismatch
+		}
+		if err := s.UpdateRow(ctx, Row{JobID: "job-1", ID: i, Status: status}); err != nil {
+			t.Fatalf("UpdateRow() error = %v", err)
+		}
+	}
+
+	mismatches, err := s.ListRows(ctx, "job-1", RowStatusMismatch, 0, 0)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if len(mismatches) != 3 {
+		t.Fatalf("ListRows(mismatch) returned %d rows, want 3", len(mismatches))
+	}
+
+	page, err := s.ListRows(ctx, "job-1", "", 1, 2)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ID != 1 {
+		t.Fatalf("ListRows(offset=1,limit=2) = %+v, want rows [1,2]", page)
+	}
+}
+
+func TestDeleteJobCascadesRows(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	rows := []Row{{ID: 0, DNI: "11111111"}, {ID: 1, DNI: "22222222"}}
+	if _, err := s.CreateJob(ctx, "job-1", rows); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if err := s.DeleteJob(ctx, "job-1"); err != nil {
+		t.Fatalf("DeleteJob() error = %v", err)
+	}
+
+	if _, err := s.GetJob(ctx, "job-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetJob() after delete error = %v, want ErrNotFound", err)
+	}
+
+	remaining, err := s.ListRows(ctx, "job-1", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListRows() after delete error = %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("ListRows() after DeleteJob returned %d rows, want 0 (foreign_keys cascade should have removed them)", len(remaining))
+	}
+}
+
+func TestDeleteJobNotFound(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.DeleteJob(context.Background(), "nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteJob() error = %v, want ErrNotFound", err)
+	}
+}
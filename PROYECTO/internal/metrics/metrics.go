@@ -0,0 +1,58 @@
+// Package metrics centraliza las métricas Prometheus de la aplicación para
+// que paquetes que no se conocen entre sí (provider, jobstore, main)
+// puedan instrumentarse sin pasarse un *prometheus.Registry de un lado a
+// otro.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// DNILookupsTotal cuenta cada consulta DNI resuelta, por proveedor y
+	// resultado (ok, error).
+	DNILookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dni_lookups_total",
+		Help: "Consultas DNI realizadas, por proveedor y resultado.",
+	}, []string{"provider", "status"})
+
+	// DNILookupDuration mide cuánto tarda cada proveedor en responder.
+	DNILookupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "dni_lookup_duration_seconds",
+		Help: "Duración de una consulta DNI a un proveedor.",
+	}, []string{"provider"})
+
+	// JobDuration mide cuánto tarda un job completo, de punta a punta.
+	JobDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "job_duration_seconds",
+		Help: "Duración total de un job de validación masiva.",
+	})
+
+	// ActiveJobs son los jobs actualmente en procesamiento.
+	ActiveJobs = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "active_jobs",
+		Help: "Jobs de validación actualmente en procesamiento.",
+	})
+
+	// WorkerInflight son los workers del pool adaptativo ocupados ahora.
+	WorkerInflight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_inflight",
+		Help: "Workers del pool adaptativo ocupados en este momento.",
+	})
+
+	// EffectiveRate es la tasa vigente (requests/segundo) del limiter
+	// AIMD de cada proveedor, que puede estar por debajo de lo
+	// configurado en providers.yaml si hubo throttling reciente.
+	EffectiveRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_effective_rate",
+		Help: "Tasa efectiva (requests/segundo) del limiter AIMD de un proveedor.",
+	}, []string{"provider"})
+
+	// ProviderRetriesTotal cuenta reintentos contra un proveedor, por motivo
+	// (rate_limited, error).
+	ProviderRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_retries_total",
+		Help: "Reintentos contra un proveedor DNI, por motivo.",
+	}, []string{"provider", "reason"})
+)
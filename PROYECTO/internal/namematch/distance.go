@@ -0,0 +1,56 @@
+package namematch
+
+// DamerauLevenshtein calcula la distancia de edición óptima entre a y b,
+// incluyendo transposiciones de caracteres adyacentes (un typo común:
+// dos letras cambiadas de lugar cuentan como un solo paso, no dos
+// sustituciones).
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+
+	if la == 0 {
+		return lb
+	}
+	if lb == 0 {
+		return la
+	}
+
+	// Matriz (la+1) x (lb+1); d[i][j] = distancia entre ra[:i] y rb[:j].
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(
+				d[i-1][j]+1,      // borrado
+				d[i][j-1]+1,      // inserción
+				d[i-1][j-1]+cost, // sustitución
+			)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min2(d[i][j], d[i-2][j-2]+cost) // transposición
+			}
+		}
+	}
+	return d[la][lb]
+}
+
+func min2(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func min3(a, b, c int) int {
+	return min2(min2(a, b), c)
+}
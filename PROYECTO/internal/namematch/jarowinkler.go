@@ -0,0 +1,98 @@
+package namematch
+
+// jaroWinklerPrefixWeight y jaroWinklerMaxPrefix son los parámetros
+// estándar del algoritmo (Winkler, 1990).
+const (
+	jaroWinklerPrefixWeight = 0.1
+	jaroWinklerMaxPrefix    = 4
+)
+
+// JaroWinkler devuelve una similitud en [0, 1]; 1 es coincidencia exacta.
+// Favorece nombres que comparten un prefijo, lo que lo hace bueno para
+// nombres propios (un typo al final de la palabra pesa menos).
+func JaroWinkler(a, b string) float64 {
+	jaro := jaroSimilarity(a, b)
+	if jaro == 0 {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	prefix := 0
+	maxPrefix := jaroWinklerMaxPrefix
+	if len(ra) < maxPrefix {
+		maxPrefix = len(ra)
+	}
+	if len(rb) < maxPrefix {
+		maxPrefix = len(rb)
+	}
+	for i := 0; i < maxPrefix; i++ {
+		if ra[i] != rb[i] {
+			break
+		}
+		prefix++
+	}
+
+	return jaro + float64(prefix)*jaroWinklerPrefixWeight*(1-jaro)
+}
+
+func jaroSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+	if la == 0 && lb == 0 {
+		return 1
+	}
+	if la == 0 || lb == 0 {
+		return 0
+	}
+
+	matchDistance := max2(la, lb)/2 - 1
+	if matchDistance < 0 {
+		matchDistance = 0
+	}
+
+	aMatches := make([]bool, la)
+	bMatches := make([]bool, lb)
+
+	matches := 0
+	for i := 0; i < la; i++ {
+		start := max2(0, i-matchDistance)
+		end := min2(lb-1, i+matchDistance)
+		for j := start; j <= end; j++ {
+			if bMatches[j] || ra[i] != rb[j] {
+				continue
+			}
+			aMatches[i] = true
+			bMatches[j] = true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	k := 0
+	for i := 0; i < la; i++ {
+		if !aMatches[i] {
+			continue
+		}
+		for !bMatches[k] {
+			k++
+		}
+		if ra[i] != rb[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	return (m/float64(la) + m/float64(lb) + (m-float64(transpositions)/2)/m) / 3
+}
+
+func max2(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,31 @@
+// Package namematch compara nombres con varias estrategias en cascada
+// (exacto, normalizado, difuso) en vez de un único strings.EqualFold, que
+// produce falsos mismatches con acentos, la Ñ, apellidos compuestos y
+// errores de tipeo típicos de RENIEC.
+package namematch
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stripDiacritics quita marcas diacríticas (acentos) tras descomponer en
+// NFD, de forma que "JOSÉ" y "JOSE" normalicen igual.
+var stripDiacritics = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Normalize aplica NFD + strip de diacríticos + colapso de espacios +
+// mayúsculas, la base que comparten todas las estrategias de esta
+// paquete.
+func Normalize(s string) string {
+	out, _, err := transform.String(stripDiacritics, s)
+	if err != nil {
+		out = s
+	}
+	out = strings.ToUpper(out)
+	out = strings.Join(strings.Fields(out), " ")
+	return out
+}
@@ -0,0 +1,17 @@
+package namematch
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"José Pérez":      "JOSE PEREZ",
+		"MUÑOZ":           "MUNOZ",
+		"  ana   maria  ": "ANA MARIA",
+		"DE LA CRUZ":      "DE LA CRUZ",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
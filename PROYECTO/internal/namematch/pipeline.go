@@ -0,0 +1,119 @@
+package namematch
+
+// Level clasifica qué tan cerca está una comparación, para que la UI
+// pueda distinguir un mismatch real de un "casi" (p. ej. un acento
+// perdido o un typo de una letra) en vez de un simple sí/no.
+type Level int
+
+const (
+	// Mismatch: por debajo de todos los umbrales configurados.
+	Mismatch Level = iota
+	// Fuzzy: coincide por distancia de edición, Jaro-Winkler o token-set,
+	// pero no tras sólo normalizar.
+	Fuzzy
+	// Normalized: distinto byte a byte pero igual tras Normalize (acentos,
+	// mayúsculas, espacios).
+	Normalized
+	// Exact: idéntico byte a byte.
+	Exact
+)
+
+func (l Level) String() string {
+	switch l {
+	case Exact:
+		return "exact"
+	case Normalized:
+		return "normalized"
+	case Fuzzy:
+		return "fuzzy"
+	default:
+		return "mismatch"
+	}
+}
+
+// Result es el resultado de comparar dos nombres con el Pipeline.
+type Result struct {
+	Score float64
+	Level Level
+}
+
+// Config ajusta los umbrales de las estrategias difusas. El cero valor no
+// es utilizable directamente: usar DefaultConfig o NewPipeline, que
+// aplica los valores por defecto cuando un campo queda en cero.
+type Config struct {
+	// MaxEditDistance es la distancia Damerau-Levenshtein máxima (en
+	// caracteres, sobre el nombre normalizado) para considerar un par
+	// "Fuzzy". Por defecto 2.
+	MaxEditDistance int
+	// JaroWinklerThreshold es la similitud mínima (0-1) para considerar un
+	// par "Fuzzy" quedando por encima de ese umbral. Por defecto 0.92.
+	JaroWinklerThreshold float64
+}
+
+// DefaultConfig son los umbrales recomendados para nombres en español.
+func DefaultConfig() Config {
+	return Config{
+		MaxEditDistance:      2,
+		JaroWinklerThreshold: 0.92,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxEditDistance <= 0 {
+		c.MaxEditDistance = DefaultConfig().MaxEditDistance
+	}
+	if c.JaroWinklerThreshold <= 0 {
+		c.JaroWinklerThreshold = DefaultConfig().JaroWinklerThreshold
+	}
+	return c
+}
+
+// Pipeline encadena las estrategias de comparación de nombres: exacto,
+// normalizado y, si ninguno de los dos aplica, las difusas (distancia de
+// edición, Jaro-Winkler y token-set). Es seguro para llamadas
+// concurrentes: no guarda estado entre Compare.
+type Pipeline struct {
+	cfg Config
+}
+
+// NewPipeline crea un Pipeline con la configuración dada, aplicando los
+// valores por defecto a los campos que queden en cero.
+func NewPipeline(cfg Config) Pipeline {
+	return Pipeline{cfg: cfg.withDefaults()}
+}
+
+// DefaultPipeline es el Pipeline usado cuando un job no trae su propia
+// Config.
+func DefaultPipeline() Pipeline {
+	return NewPipeline(DefaultConfig())
+}
+
+// Compare clasifica el par (a, b) según la cascada exacto → normalizado →
+// difuso → mismatch.
+func (p Pipeline) Compare(a, b string) Result {
+	if a == b {
+		return Result{Score: 1, Level: Exact}
+	}
+
+	na, nb := Normalize(a), Normalize(b)
+	if na == nb {
+		return Result{Score: 1, Level: Normalized}
+	}
+
+	jw := JaroWinkler(na, nb)
+	tsr := TokenSetRatio(na, nb)
+	score := jw
+	if tsr > score {
+		score = tsr
+	}
+
+	dist := DamerauLevenshtein(na, nb)
+	withinEditDistance := dist <= p.cfg.MaxEditDistance
+	withinJaroWinkler := jw >= p.cfg.JaroWinklerThreshold || tsr >= p.cfg.JaroWinklerThreshold
+
+	if withinEditDistance || withinJaroWinkler {
+		return Result{Score: score, Level: Fuzzy}
+	}
+
+	return Result{Score: score, Level: Mismatch}
+}
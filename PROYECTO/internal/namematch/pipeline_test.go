@@ -0,0 +1,70 @@
+package namematch
+
+import "testing"
+
+func TestCompareExact(t *testing.T) {
+	r := DefaultPipeline().Compare("JOSE PEREZ", "JOSE PEREZ")
+	if r.Level != Exact || r.Score != 1 {
+		t.Fatalf("got %+v, want Exact/1", r)
+	}
+}
+
+func TestCompareNormalized(t *testing.T) {
+	cases := [][2]string{
+		{"JOSÉ PEREZ", "JOSE PEREZ"},       // acento
+		{"MUÑOZ", "MUNOZ"},                 // Ñ
+		{"  JOSE   PEREZ ", "JOSE PEREZ"},  // espacios
+		{"jose perez", "JOSE PEREZ"},       // mayúsculas
+	}
+	for _, c := range cases {
+		r := DefaultPipeline().Compare(c[0], c[1])
+		if r.Level != Normalized {
+			t.Errorf("Compare(%q, %q) = %v, want Normalized", c[0], c[1], r.Level)
+		}
+	}
+}
+
+func TestCompareFuzzyTypo(t *testing.T) {
+	r := DefaultPipeline().Compare("JOSE PEREZ", "JOSE PERES")
+	if r.Level != Fuzzy {
+		t.Fatalf("got %v, want Fuzzy", r.Level)
+	}
+}
+
+func TestCompareFuzzyTokenOrder(t *testing.T) {
+	r := DefaultPipeline().Compare("PEREZ DE LA CRUZ JOSE", "JOSE PEREZ DE LA CRUZ")
+	if r.Level != Fuzzy && r.Level != Normalized {
+		t.Fatalf("got %v, want Fuzzy or Normalized for reordered tokens", r.Level)
+	}
+}
+
+func TestCompareMismatch(t *testing.T) {
+	r := DefaultPipeline().Compare("JOSE PEREZ", "MARIA QUISPE")
+	if r.Level != Mismatch {
+		t.Fatalf("got %v, want Mismatch", r.Level)
+	}
+}
+
+func TestConfigWithDefaults(t *testing.T) {
+	p := NewPipeline(Config{})
+	if p.cfg.MaxEditDistance != DefaultConfig().MaxEditDistance {
+		t.Fatalf("zero-value MaxEditDistance should fall back to default")
+	}
+	if p.cfg.JaroWinklerThreshold != DefaultConfig().JaroWinklerThreshold {
+		t.Fatalf("zero-value JaroWinklerThreshold should fall back to default")
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	cases := map[Level]string{
+		Exact:      "exact",
+		Normalized: "normalized",
+		Fuzzy:      "fuzzy",
+		Mismatch:   "mismatch",
+	}
+	for level, want := range cases {
+		if got := level.String(); got != want {
+			t.Errorf("Level(%d).String() = %q, want %q", level, got, want)
+		}
+	}
+}
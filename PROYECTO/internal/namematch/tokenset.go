@@ -0,0 +1,48 @@
+package namematch
+
+import (
+	"sort"
+	"strings"
+)
+
+// TokenSetRatio compara a y b por conjunto de palabras en vez de por
+// posición, así "DE LA CRUZ MAMANI" coincide con "MAMANI DE LA CRUZ": el
+// orden de los apellidos varía entre fuentes y no debería contar como
+// mismatch. Devuelve una similitud en [0, 1].
+func TokenSetRatio(a, b string) float64 {
+	ta := tokenSet(a)
+	tb := tokenSet(b)
+
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	sortedA := strings.Join(sortedTokens(ta), " ")
+	sortedB := strings.Join(sortedTokens(tb), " ")
+	if sortedA == sortedB {
+		return 1
+	}
+
+	return JaroWinkler(sortedA, sortedB)
+}
+
+func tokenSet(s string) map[string]struct{} {
+	tokens := strings.Fields(Normalize(s))
+	set := make(map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		set[t] = struct{}{}
+	}
+	return set
+}
+
+func sortedTokens(set map[string]struct{}) []string {
+	out := make([]string, 0, len(set))
+	for t := range set {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// cacheEntry es lo que se guarda por DNI, tanto en memoria como en disco.
+type cacheEntry struct {
+	Person    Person    `json:"person"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// persistInterval es cada cuánto se vuelca el cache a disco cuando hay
+// escrituras pendientes. Desacopla el costo de serializar+escribir del
+// camino caliente de cada lookup: un batch de 10k DNIs nuevos produce un
+// puñado de escrituras (una por intervalo), no una por entrada.
+const persistInterval = 2 * time.Second
+
+// Cache es un LRU en memoria respaldado por un archivo JSON en disco, para
+// no perder las entradas entre reinicios del proceso. Evita pagar de
+// nuevo una consulta ya resuelta dentro de TTL — las APIs de RENIEC
+// cobran por lookup, así que esto es plata real.
+type Cache struct {
+	path     string
+	ttl      time.Duration
+	maxItems int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // más reciente al frente
+	dirty   bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+type cacheNode struct {
+	dni   string
+	entry cacheEntry
+}
+
+// NewCache abre (o crea) el archivo de cache en path. maxItems acota la
+// memoria usada; ttl es cuánto tiempo se confía en una entrada cacheada.
+func NewCache(path string, ttl time.Duration, maxItems int) (*Cache, error) {
+	c := &Cache{
+		path:     path,
+		ttl:      ttl,
+		maxItems: maxItems,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	go c.flushLoop()
+	return c, nil
+}
+
+// flushLoop vuelca el cache a disco cada persistInterval, y sólo si hubo
+// escrituras desde el último vuelco. Corre hasta que Close lo detiene.
+func (c *Cache) flushLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.stop:
+			c.flush()
+			return
+		}
+	}
+}
+
+func (c *Cache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return
+	}
+	if err := c.persist(); err == nil {
+		c.dirty = false
+	}
+}
+
+// Close detiene el flusher en background, volcando a disco cualquier
+// escritura pendiente antes de volver.
+func (c *Cache) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+func (c *Cache) load() error {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]cacheEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	now := time.Now()
+	for dni, entry := range raw {
+		if entry.ExpiresAt.Before(now) {
+			continue
+		}
+		c.entries[dni] = c.order.PushFront(&cacheNode{dni: dni, entry: entry})
+	}
+	return nil
+}
+
+// persist vuelca el estado actual del cache a disco. Se llama con c.mu ya
+// tomado, desde flush en el flusher en background — nunca desde put, para
+// no pagar una serialización completa del mapa en cada escritura.
+func (c *Cache) persist() error {
+	raw := make(map[string]cacheEntry, len(c.entries))
+	for dni, el := range c.entries {
+		raw[dni] = el.Value.(*cacheNode).entry
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+func (c *Cache) get(dni string) (Person, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[dni]
+	if !ok {
+		return Person{}, false
+	}
+	node := el.Value.(*cacheNode)
+	if node.entry.ExpiresAt.Before(time.Now()) {
+		c.order.Remove(el)
+		delete(c.entries, dni)
+		return Person{}, false
+	}
+	c.order.MoveToFront(el)
+	return node.entry.Person, true
+}
+
+func (c *Cache) put(dni string, person Person) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := cacheEntry{Person: person, ExpiresAt: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[dni]; ok {
+		el.Value.(*cacheNode).entry = entry
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[dni] = c.order.PushFront(&cacheNode{dni: dni, entry: entry})
+	}
+
+	for len(c.entries) > c.maxItems {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheNode).dni)
+	}
+
+	c.dirty = true
+}
+
+// CachingProvider envuelve un Provider y sirve desde Cache cuando hay una
+// entrada vigente, evitando el round-trip (y el costo) de la API real.
+type CachingProvider struct {
+	inner Provider
+	cache *Cache
+}
+
+// WithCache decora provider con cache.
+func WithCache(inner Provider, cache *Cache) *CachingProvider {
+	return &CachingProvider{inner: inner, cache: cache}
+}
+
+func (p *CachingProvider) Name() string { return p.inner.Name() }
+
+// Close detiene el flusher en background del cache, volcando a disco
+// cualquier escritura pendiente.
+func (p *CachingProvider) Close() { p.cache.Close() }
+
+// AllProvidersFailing delega en el Chain interno, si lo hay, para que
+// /readyz pueda detectar una configuración rota sin disparar un lookup
+// nuevo sólo para probar salud.
+func (p *CachingProvider) AllProvidersFailing() bool {
+	statter, ok := p.inner.(interface{ AllProvidersFailing() bool })
+	return ok && statter.AllProvidersFailing()
+}
+
+func (p *CachingProvider) Lookup(ctx context.Context, dni string) (Person, error) {
+	if person, ok := p.cache.get(dni); ok {
+		return person, nil
+	}
+	person, err := p.inner.Lookup(ctx, dni)
+	if err != nil {
+		return Person{}, err
+	}
+	p.cache.put(dni, person)
+	return person, nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheGetPutRoundtrip(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	defer c.Close()
+
+	c.put("12345678", Person{Nombre: "ANA"})
+	p, ok := c.get("12345678")
+	if !ok || p.Nombre != "ANA" {
+		t.Fatalf("get() = %+v, %v, want ANA, true", p, ok)
+	}
+}
+
+func TestCacheGetMissingReturnsFalse(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	defer c.Close()
+
+	if _, ok := c.get("00000000"); ok {
+		t.Fatal("get() of a never-written DNI should return false")
+	}
+}
+
+func TestCacheEvictsOldestOverMaxItems(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute, 2)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	defer c.Close()
+
+	c.put("1", Person{Nombre: "A"})
+	c.put("2", Person{Nombre: "B"})
+	c.put("3", Person{Nombre: "C"}) // should evict "1", the LRU entry
+
+	if _, ok := c.get("1"); ok {
+		t.Fatal("oldest entry should have been evicted once maxItems was exceeded")
+	}
+	if _, ok := c.get("3"); !ok {
+		t.Fatal("most recently written entry should still be cached")
+	}
+}
+
+func TestCloseFlushesPendingWritesToDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := NewCache(path, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	c.put("12345678", Person{Nombre: "ANA"})
+	c.Close() // should flush synchronously, without waiting for persistInterval
+
+	reopened, err := NewCache(path, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("reopening cache: %v", err)
+	}
+	defer reopened.Close()
+
+	p, ok := reopened.get("12345678")
+	if !ok || p.Nombre != "ANA" {
+		t.Fatalf("get() after reopen = %+v, %v, want ANA, true", p, ok)
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Stats acumula el historial de éxito/falla de un proveedor dentro de una
+// Chain, para poder exponerlo (p.ej. por /metrics más adelante).
+type Stats struct {
+	Attempts int
+	Failures int
+}
+
+// Chain intenta una lista de proveedores en orden y pasa al siguiente
+// cuando el actual falla con un error "vale la pena reintentar en otro
+// lado" (ErrNotFound no cuenta: si un proveedor confirma que el DNI no
+// existe, no tiene sentido preguntarle a otro).
+type Chain struct {
+	providers []Provider
+
+	mu         sync.Mutex
+	stats      map[string]*Stats
+	lastFailed map[string]bool
+}
+
+// NewChain arma una cadena de failover. El orden de providers es el orden
+// de intento.
+func NewChain(providers ...Provider) *Chain {
+	stats := make(map[string]*Stats, len(providers))
+	for _, p := range providers {
+		stats[p.Name()] = &Stats{}
+	}
+	return &Chain{providers: providers, stats: stats, lastFailed: make(map[string]bool, len(providers))}
+}
+
+func (c *Chain) Name() string { return "chain" }
+
+// Lookup prueba cada proveedor en orden hasta obtener una respuesta o
+// agotar la lista. Sólo falla al siguiente proveedor ante timeouts,
+// ErrRateLimited o ErrUnavailable (o cualquier error de transporte sin
+// clasificar); ErrNotFound se propaga de inmediato.
+func (c *Chain) Lookup(ctx context.Context, dni string) (Person, error) {
+	var lastErr error
+	for _, p := range c.providers {
+		c.record(p.Name(), true)
+
+		person, err := p.Lookup(ctx, dni)
+		if err == nil {
+			c.markLastResult(p.Name(), false)
+			return person, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			c.markLastResult(p.Name(), false)
+			return Person{}, err
+		}
+
+		c.record(p.Name(), false)
+		c.markLastResult(p.Name(), true)
+		lastErr = err
+	}
+	if lastErr == nil {
+		return Person{}, fmt.Errorf("provider chain: no hay proveedores configurados")
+	}
+	return Person{}, fmt.Errorf("provider chain: todos los proveedores fallaron, último error: %w", lastErr)
+}
+
+func (c *Chain) record(name string, attempt bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[name]
+	if !ok {
+		s = &Stats{}
+		c.stats[name] = s
+	}
+	if attempt {
+		s.Attempts++
+	} else {
+		s.Failures++
+	}
+}
+
+// Stats devuelve una copia del historial de éxito/falla por proveedor,
+// acumulado desde que arrancó el proceso.
+func (c *Chain) Stats() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]Stats, len(c.stats))
+	for name, s := range c.stats {
+		out[name] = *s
+	}
+	return out
+}
+
+func (c *Chain) markLastResult(name string, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastFailed[name] = failed
+}
+
+// AllProvidersFailing indica si el intento más reciente contra cada
+// proveedor de la cadena falló, señal de que ahora mismo no hay forma de
+// resolver un DNI (credenciales o base_url rotos en providers.yaml, o
+// rate limiting sostenido). Mira sólo el último resultado por proveedor,
+// no el historial acumulado completo, para no quedar "sano" para
+// siempre sólo porque un proveedor funcionó alguna vez al arrancar el
+// proceso. No dispara ninguna consulta extra (y por lo tanto no cuesta
+// nada) sólo para chequear salud: se apoya en los intentos que ya
+// ocurrieron por tráfico real.
+func (c *Chain) AllProvidersFailing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.stats) == 0 {
+		return false
+	}
+	for name, s := range c.stats {
+		if s.Attempts == 0 || !c.lastFailed[name] {
+			return false
+		}
+	}
+	return true
+}
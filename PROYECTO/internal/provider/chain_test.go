@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChainReturnsFirstSuccess(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Data: map[string]Person{"12345678": {Nombre: "ANA"}}}
+	chain := NewChain(primary)
+
+	p, err := chain.Lookup(context.Background(), "12345678")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if p.Nombre != "ANA" {
+		t.Fatalf("Lookup() = %+v, want Nombre=ANA", p)
+	}
+}
+
+func TestChainFailsOverOnUnavailable(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Err: ErrUnavailable}
+	secondary := &Mock{ProviderName: "secondary", Data: map[string]Person{"12345678": {Nombre: "ANA"}}}
+	chain := NewChain(primary, secondary)
+
+	p, err := chain.Lookup(context.Background(), "12345678")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if p.Nombre != "ANA" {
+		t.Fatalf("Lookup() = %+v, want Nombre=ANA from secondary", p)
+	}
+
+	stats := chain.Stats()
+	if stats["primary"].Failures != 1 {
+		t.Fatalf("primary Failures = %d, want 1", stats["primary"].Failures)
+	}
+	if stats["secondary"].Attempts != 1 || stats["secondary"].Failures != 0 {
+		t.Fatalf("secondary stats = %+v, want one successful attempt", stats["secondary"])
+	}
+}
+
+func TestChainDoesNotFailOverOnNotFound(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Err: ErrNotFound}
+	secondary := &Mock{ProviderName: "secondary", Data: map[string]Person{"12345678": {Nombre: "ANA"}}}
+	chain := NewChain(primary, secondary)
+
+	_, err := chain.Lookup(context.Background(), "12345678")
+	if err != ErrNotFound {
+		t.Fatalf("Lookup() error = %v, want ErrNotFound (should not have tried secondary)", err)
+	}
+	if stats := chain.Stats(); stats["secondary"].Attempts != 0 {
+		t.Fatalf("secondary should never have been tried, got %+v", stats["secondary"])
+	}
+}
+
+func TestChainReturnsErrorWhenAllFail(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Err: ErrUnavailable}
+	secondary := &Mock{ProviderName: "secondary", Err: ErrRateLimited}
+	chain := NewChain(primary, secondary)
+
+	if _, err := chain.Lookup(context.Background(), "12345678"); err == nil {
+		t.Fatal("Lookup() should fail when every provider fails")
+	}
+}
+
+func TestChainAllProvidersFailing(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Err: ErrUnavailable}
+	secondary := &Mock{ProviderName: "secondary", Err: ErrRateLimited}
+	chain := NewChain(primary, secondary)
+
+	if chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = true before any Lookup, want false")
+	}
+
+	chain.Lookup(context.Background(), "12345678")
+	if !chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = false after every provider failed, want true")
+	}
+}
+
+func TestChainAllProvidersFailingFalseAfterOneSucceeds(t *testing.T) {
+	primary := &Mock{ProviderName: "primary", Err: ErrUnavailable}
+	secondary := &Mock{ProviderName: "secondary", Data: map[string]Person{"12345678": {Nombre: "ANA"}}}
+	chain := NewChain(primary, secondary)
+
+	chain.Lookup(context.Background(), "12345678")
+	if chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = true, want false: secondary just succeeded")
+	}
+}
+
+func TestChainAllProvidersFailingReflectsMostRecentAttempt(t *testing.T) {
+	p := &Mock{ProviderName: "primary", Data: map[string]Person{"12345678": {Nombre: "ANA"}}}
+	chain := NewChain(p)
+
+	chain.Lookup(context.Background(), "12345678")
+	if chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = true after a success, want false")
+	}
+
+	p.Err, p.Data = ErrUnavailable, nil
+	chain.Lookup(context.Background(), "12345678")
+	if !chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = false after the provider started failing, want true: an old success shouldn't keep it green forever")
+	}
+
+	p.Err, p.Data = nil, map[string]Person{"12345678": {Nombre: "ANA"}}
+	chain.Lookup(context.Background(), "12345678")
+	if chain.AllProvidersFailing() {
+		t.Fatal("AllProvidersFailing() = true after the provider recovered, want false")
+	}
+}
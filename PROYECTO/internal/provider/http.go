@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpConfig es la configuración compartida por los proveedores basados en
+// un endpoint HTTP simple de tipo "GET ?numero=<dni>".
+type httpConfig struct {
+	name    string
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func newHTTPConfig(name, baseURL, token string) httpConfig {
+	return httpConfig{
+		name:    name,
+		baseURL: baseURL,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// classifyStatus traduce un código HTTP al vocabulario de errores de
+// Provider, para que Chain no tenga que conocer cada API externa.
+func classifyStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusOK:
+		return nil
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= 500:
+		return ErrUnavailable
+	default:
+		return fmt.Errorf("provider: HTTP %d inesperado", statusCode)
+	}
+}
+
+func (c httpConfig) doLookup(ctx context.Context, url string, decode func([]byte) (Person, error)) (Person, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Person{}, fmt.Errorf("provider %s: construyendo request: %w", c.name, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("User-Agent", "Go-Validator/3.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// *url.Error incluye la URL, que lleva el DNI: se descarta para que
+		// el error pueda terminar en logs sin exponerlo.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return Person{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if err := classifyStatus(resp.StatusCode); err != nil {
+		return Person{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Person{}, fmt.Errorf("provider %s: leyendo respuesta: %w", c.name, err)
+	}
+	return decode(body)
+}
+
+// DecolectaProvider consulta api.decolecta.com, el proveedor original de
+// este proyecto.
+type DecolectaProvider struct{ cfg httpConfig }
+
+func NewDecolecta(baseURL, token string) *DecolectaProvider {
+	return &DecolectaProvider{cfg: newHTTPConfig("decolecta", baseURL, token)}
+}
+
+func (p *DecolectaProvider) Name() string { return p.cfg.name }
+
+func (p *DecolectaProvider) Lookup(ctx context.Context, dni string) (Person, error) {
+	return p.cfg.doLookup(ctx, p.cfg.baseURL+dni, func(body []byte) (Person, error) {
+		var data struct {
+			FirstName      string `json:"first_name"`
+			FirstLastName  string `json:"first_last_name"`
+			SecondLastName string `json:"second_last_name"`
+		}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return Person{}, fmt.Errorf("provider decolecta: JSON inválido: %w", err)
+		}
+		return Person{Nombre: data.FirstName, Paterno: data.FirstLastName, Materno: data.SecondLastName}, nil
+	})
+}
+
+// ApisPeruProvider consulta apisperu.com como alternativa a Decolecta.
+type ApisPeruProvider struct{ cfg httpConfig }
+
+func NewApisPeru(baseURL, token string) *ApisPeruProvider {
+	return &ApisPeruProvider{cfg: newHTTPConfig("apisperu", baseURL, token)}
+}
+
+func (p *ApisPeruProvider) Name() string { return p.cfg.name }
+
+func (p *ApisPeruProvider) Lookup(ctx context.Context, dni string) (Person, error) {
+	return p.cfg.doLookup(ctx, p.cfg.baseURL+dni, func(body []byte) (Person, error) {
+		var data struct {
+			Nombres         string `json:"nombres"`
+			ApellidoPaterno string `json:"apellidoPaterno"`
+			ApellidoMaterno string `json:"apellidoMaterno"`
+		}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return Person{}, fmt.Errorf("provider apisperu: JSON inválido: %w", err)
+		}
+		return Person{Nombre: data.Nombres, Paterno: data.ApellidoPaterno, Materno: data.ApellidoMaterno}, nil
+	})
+}
+
+// ApisNetProvider consulta apis.net.pe como segunda alternativa.
+type ApisNetProvider struct{ cfg httpConfig }
+
+func NewApisNet(baseURL, token string) *ApisNetProvider {
+	return &ApisNetProvider{cfg: newHTTPConfig("apisnet", baseURL, token)}
+}
+
+func (p *ApisNetProvider) Name() string { return p.cfg.name }
+
+func (p *ApisNetProvider) Lookup(ctx context.Context, dni string) (Person, error) {
+	return p.cfg.doLookup(ctx, p.cfg.baseURL+dni, func(body []byte) (Person, error) {
+		var data struct {
+			Nombres         string `json:"nombres"`
+			ApellidoPaterno string `json:"apellidoPaterno"`
+			ApellidoMaterno string `json:"apellidoMaterno"`
+		}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return Person{}, fmt.Errorf("provider apisnet: JSON inválido: %w", err)
+		}
+		return Person{Nombre: data.Nombres, Paterno: data.ApellidoPaterno, Materno: data.ApellidoMaterno}, nil
+	})
+}
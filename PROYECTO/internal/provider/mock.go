@@ -0,0 +1,29 @@
+package provider
+
+import "context"
+
+// Mock es un Provider en memoria para tests: responde lo que haya en Data,
+// o ErrNotFound si el DNI no está registrado.
+type Mock struct {
+	ProviderName string
+	Data         map[string]Person
+	Err          error
+}
+
+func (m *Mock) Name() string {
+	if m.ProviderName == "" {
+		return "mock"
+	}
+	return m.ProviderName
+}
+
+func (m *Mock) Lookup(ctx context.Context, dni string) (Person, error) {
+	if m.Err != nil {
+		return Person{}, m.Err
+	}
+	p, ok := m.Data[dni]
+	if !ok {
+		return Person{}, ErrNotFound
+	}
+	return p, nil
+}
@@ -0,0 +1,36 @@
+// Package provider define la interfaz para consultar datos RENIEC por DNI
+// y las implementaciones concretas (Decolecta, ApisPeru, ApisNet, un mock
+// para tests), para que el resto de la aplicación no dependa de una sola
+// API externa.
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// Person son los datos que devuelve cualquier proveedor para un DNI.
+type Person struct {
+	Nombre  string
+	Paterno string
+	Materno string
+}
+
+// Errores comunes que un Provider puede devolver; Chain los usa
+// para decidir si vale la pena intentar el siguiente proveedor.
+var (
+	ErrNotFound    = errors.New("provider: DNI no encontrado")
+	ErrRateLimited = errors.New("provider: límite de tasa excedido")
+	ErrUnavailable = errors.New("provider: proveedor no disponible")
+)
+
+// Provider resuelve los datos RENIEC de un DNI contra una fuente concreta.
+type Provider interface {
+	// Name identifica al proveedor en logs, métricas y providers.yaml.
+	Name() string
+	// Lookup consulta un DNI. Debe devolver ErrNotFound, ErrRateLimited o
+	// ErrUnavailable cuando aplique, para que Chain pueda
+	// reaccionar sin tener que inspeccionar el error concreto del
+	// transporte HTTP.
+	Lookup(ctx context.Context, dni string) (Person, error)
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"proyecto/internal/metrics"
+	"proyecto/internal/ratelimit"
+)
+
+// RateLimitedProvider aplica un token bucket con ajuste AIMD antes de cada
+// Lookup, y retroalimenta al limiter con el resultado: un 429 lo encoge,
+// una racha de éxitos lo deja crecer de nuevo hacia el máximo configurado.
+type RateLimitedProvider struct {
+	inner   Provider
+	limiter *ratelimit.Limiter
+}
+
+// WithRateLimit decora inner con limiter.
+func WithRateLimit(inner Provider, limiter *ratelimit.Limiter) *RateLimitedProvider {
+	return &RateLimitedProvider{inner: inner, limiter: limiter}
+}
+
+func (p *RateLimitedProvider) Name() string { return p.inner.Name() }
+
+func (p *RateLimitedProvider) Lookup(ctx context.Context, dni string) (Person, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return Person{}, err
+	}
+
+	start := time.Now()
+	person, err := p.inner.Lookup(ctx, dni)
+	metrics.DNILookupDuration.WithLabelValues(p.Name()).Observe(time.Since(start).Seconds())
+
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		p.limiter.OnThrottled()
+		metrics.ProviderRetriesTotal.WithLabelValues(p.Name(), "rate_limited").Inc()
+		metrics.DNILookupsTotal.WithLabelValues(p.Name(), "error").Inc()
+	case errors.Is(err, ErrNotFound):
+		// Ni Chain ni runJob reintentan un DNI inexistente: no es un reintento.
+		metrics.DNILookupsTotal.WithLabelValues(p.Name(), "error").Inc()
+	case err != nil:
+		metrics.ProviderRetriesTotal.WithLabelValues(p.Name(), "error").Inc()
+		metrics.DNILookupsTotal.WithLabelValues(p.Name(), "error").Inc()
+	default:
+		p.limiter.OnSuccess()
+		metrics.DNILookupsTotal.WithLabelValues(p.Name(), "ok").Inc()
+	}
+	metrics.EffectiveRate.WithLabelValues(p.Name()).Set(p.limiter.EffectiveRate())
+	return person, err
+}
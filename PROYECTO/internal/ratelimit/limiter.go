@@ -0,0 +1,97 @@
+// Package ratelimit reemplaza el viejo sleep fijo + semáforo por un token
+// bucket con ajuste AIMD (additive-increase / multiplicative-decrease) y un
+// pool de workers que crece o encoge según la latencia observada, en vez de
+// un número de hilos fijo a mano.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// aimdDecreaseFactor es cuánto se reduce la tasa ante un 429/Retry-After.
+	aimdDecreaseFactor = 0.5
+	// aimdIncreaseEvery es cada cuántos éxitos consecutivos se intenta
+	// recuperar tasa hacia el máximo configurado.
+	aimdIncreaseEvery = 20
+)
+
+// Limiter envuelve un golang.org/x/time/rate.Limiter cuya tasa se ajusta
+// con AIMD: se reduce a la mitad ante señales de throttling del proveedor,
+// y sube de a pasos pequeños tras una racha de éxitos.
+type Limiter struct {
+	mu         sync.Mutex
+	limiter    *rate.Limiter
+	currentRPS float64
+	maxRPS     float64
+	minRPS     float64
+	successRun int
+}
+
+// New crea un Limiter configurado con la tasa y ráfaga máximas de
+// providers.yaml (rps, burst).
+func New(rps float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	if rps <= 0 {
+		rps = 1
+	}
+	return &Limiter{
+		limiter:    rate.NewLimiter(rate.Limit(rps), burst),
+		currentRPS: rps,
+		maxRPS:     rps,
+		minRPS:     rps / 8,
+	}
+}
+
+// Wait bloquea hasta que haya un token disponible o ctx se cancele.
+func (l *Limiter) Wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
+
+// OnThrottled aplica la mitad "multiplicative decrease": se usa cuando el
+// proveedor responde 429 o manda Retry-After.
+func (l *Limiter) OnThrottled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.successRun = 0
+	l.currentRPS *= aimdDecreaseFactor
+	if l.currentRPS < l.minRPS {
+		l.currentRPS = l.minRPS
+	}
+	l.limiter.SetLimit(rate.Limit(l.currentRPS))
+}
+
+// OnSuccess cuenta un éxito y, cada aimdIncreaseEvery seguidos, sube la
+// tasa un paso hacia el máximo ("additive increase").
+func (l *Limiter) OnSuccess() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.successRun++
+	if l.successRun < aimdIncreaseEvery {
+		return
+	}
+	l.successRun = 0
+	if l.currentRPS >= l.maxRPS {
+		return
+	}
+	l.currentRPS += l.maxRPS / 10
+	if l.currentRPS > l.maxRPS {
+		l.currentRPS = l.maxRPS
+	}
+	l.limiter.SetLimit(rate.Limit(l.currentRPS))
+}
+
+// EffectiveRate devuelve la tasa vigente (puede ser menor que la
+// configurada si hubo throttling reciente).
+func (l *Limiter) EffectiveRate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.currentRPS
+}
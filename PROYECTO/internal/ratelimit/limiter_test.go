@@ -0,0 +1,55 @@
+package ratelimit
+
+import "testing"
+
+func TestNewClampsDefaults(t *testing.T) {
+	l := New(0, 0)
+	if l.EffectiveRate() != 1 {
+		t.Fatalf("rps<=0 should clamp to 1, got %v", l.EffectiveRate())
+	}
+}
+
+func TestOnThrottledHalvesRate(t *testing.T) {
+	l := New(10, 10)
+	l.OnThrottled()
+	if got := l.EffectiveRate(); got != 5 {
+		t.Fatalf("EffectiveRate() = %v, want 5", got)
+	}
+}
+
+func TestOnThrottledFloorsAtMinRPS(t *testing.T) {
+	l := New(10, 10) // minRPS = 10/8 = 1.25
+	for i := 0; i < 10; i++ {
+		l.OnThrottled()
+	}
+	if got, min := l.EffectiveRate(), 10.0/8; got < min {
+		t.Fatalf("EffectiveRate() = %v, should never drop below minRPS %v", got, min)
+	}
+}
+
+func TestOnSuccessRecoversTowardMax(t *testing.T) {
+	l := New(10, 10)
+	l.OnThrottled() // currentRPS = 5
+
+	for i := 0; i < aimdIncreaseEvery-1; i++ {
+		l.OnSuccess()
+	}
+	if got := l.EffectiveRate(); got != 5 {
+		t.Fatalf("rate should not move before aimdIncreaseEvery successes, got %v", got)
+	}
+
+	l.OnSuccess() // the aimdIncreaseEvery-th success
+	if got, want := l.EffectiveRate(), 5+10.0/10; got != want {
+		t.Fatalf("EffectiveRate() = %v, want %v", got, want)
+	}
+}
+
+func TestOnSuccessNeverExceedsMaxRPS(t *testing.T) {
+	l := New(10, 10)
+	for i := 0; i < aimdIncreaseEvery*20; i++ {
+		l.OnSuccess()
+	}
+	if got := l.EffectiveRate(); got != 10 {
+		t.Fatalf("EffectiveRate() = %v, should cap at maxRPS 10", got)
+	}
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TargetP95 es la latencia p95 que el Pool intenta mantener creciendo o
+// encogiendo su tamaño.
+const TargetP95 = 2 * time.Second
+
+// sampleWindow es cuántas latencias se acumulan antes de recalcular el
+// tamaño del pool.
+const sampleWindow = 20
+
+// Pool es un semáforo de concurrencia cuyo tamaño se adapta a la latencia
+// observada, reemplazando al `MaxWorkers` fijo original: si la p95 se
+// dispara el pool encoge, si sobra margen crece (hasta maxSize).
+type Pool struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	size     int
+	minSize  int
+	maxSize  int
+	inflight int
+	samples  []time.Duration
+}
+
+// NewPool arranca con `initial` workers y permite moverse entre minSize y
+// maxSize según la latencia.
+func NewPool(initial, minSize, maxSize int) *Pool {
+	if minSize < 1 {
+		minSize = 1
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	if initial < minSize {
+		initial = minSize
+	}
+	if initial > maxSize {
+		initial = maxSize
+	}
+	p := &Pool{size: initial, minSize: minSize, maxSize: maxSize}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// Acquire bloquea hasta que haya un lugar libre en el pool.
+func (p *Pool) Acquire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.inflight >= p.size {
+		p.cond.Wait()
+	}
+	p.inflight++
+}
+
+// Release libera un lugar y registra cuánto tardó la operación, para que
+// el pool reconsidere su tamaño.
+func (p *Pool) Release(latency time.Duration) {
+	p.mu.Lock()
+	p.inflight--
+	p.recordLocked(latency)
+	p.cond.Signal()
+	p.mu.Unlock()
+}
+
+func (p *Pool) recordLocked(latency time.Duration) {
+	p.samples = append(p.samples, latency)
+	if len(p.samples) < sampleWindow {
+		return
+	}
+	p95 := percentile95(p.samples)
+	p.samples = p.samples[:0]
+
+	switch {
+	case p95 > TargetP95 && p.size > p.minSize:
+		p.size--
+	case p95 < TargetP95/2 && p.size < p.maxSize:
+		p.size++
+	}
+}
+
+func percentile95(samples []time.Duration) time.Duration {
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(float64(len(sorted)) * 0.95)
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// Inflight es cuántas operaciones están en curso ahora mismo.
+func (p *Pool) Inflight() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inflight
+}
+
+// Size es el tamaño actual del pool (puede haber crecido o encogido desde
+// el inicial).
+func (p *Pool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
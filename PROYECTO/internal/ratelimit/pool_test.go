@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPoolClampsSize(t *testing.T) {
+	p := NewPool(0, 2, 5)
+	if p.Size() != 2 {
+		t.Fatalf("initial below minSize should clamp to minSize, got %d", p.Size())
+	}
+	p = NewPool(10, 2, 5)
+	if p.Size() != 5 {
+		t.Fatalf("initial above maxSize should clamp to maxSize, got %d", p.Size())
+	}
+}
+
+func TestAcquireReleaseTracksInflight(t *testing.T) {
+	p := NewPool(2, 1, 2)
+	p.Acquire()
+	if p.Inflight() != 1 {
+		t.Fatalf("Inflight() = %d, want 1", p.Inflight())
+	}
+	p.Release(10 * time.Millisecond)
+	if p.Inflight() != 0 {
+		t.Fatalf("Inflight() = %d, want 0", p.Inflight())
+	}
+}
+
+func TestPoolShrinksOnHighLatency(t *testing.T) {
+	p := NewPool(4, 1, 4)
+	for i := 0; i < sampleWindow; i++ {
+		p.Acquire()
+		p.Release(3 * time.Second) // well above TargetP95
+	}
+	if got := p.Size(); got != 3 {
+		t.Fatalf("Size() = %d, want 3 after a window of high-latency samples", got)
+	}
+}
+
+func TestPoolGrowsOnLowLatency(t *testing.T) {
+	p := NewPool(2, 1, 4)
+	for i := 0; i < sampleWindow; i++ {
+		p.Acquire()
+		p.Release(10 * time.Millisecond) // well below TargetP95/2
+	}
+	if got := p.Size(); got != 3 {
+		t.Fatalf("Size() = %d, want 3 after a window of low-latency samples", got)
+	}
+}
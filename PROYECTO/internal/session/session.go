@@ -0,0 +1,123 @@
+// Package session firma y valida cookies de sesión con HMAC-SHA256, sin
+// depender de un store de sesiones en el servidor: todo el estado vive en
+// la cookie, firmada para que el cliente no pueda falsificarla.
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CookieName es el nombre de la cookie de sesión.
+const CookieName = "session"
+
+// TTL es cuánto dura una sesión desde que se emite.
+const TTL = 24 * time.Hour
+
+// ErrInvalid se devuelve cuando la cookie falta, está mal formada, tiene
+// una firma inválida o ya expiró.
+var ErrInvalid = errors.New("session: cookie inválida o expirada")
+
+// Manager firma y valida sesiones con una clave HMAC compartida.
+type Manager struct {
+	key []byte
+}
+
+// NewManager crea un Manager con la clave dada (p. ej. la MasterKey de
+// internal/tokencrypt).
+func NewManager(key []byte) Manager {
+	return Manager{key: key}
+}
+
+// Issue emite el valor de cookie para username, firmado y con expiración
+// TTL a partir de ahora.
+func (m Manager) Issue(username string) string {
+	expires := time.Now().Add(TTL).Unix()
+	payload := username + "|" + strconv.FormatInt(expires, 10)
+	sig := m.sign(payload)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sig
+}
+
+// SetCookie escribe la cookie de sesión en w, marcada HttpOnly, Secure y
+// SameSite=Lax.
+func (m Manager) SetCookie(w http.ResponseWriter, username string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    m.Issue(username),
+		Path:     "/",
+		MaxAge:   int(TTL.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearCookie borra la cookie de sesión (usado por /logout).
+func (m Manager) ClearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Verify valida el valor de una cookie de sesión y devuelve el username
+// que contiene.
+func (m Manager) Verify(cookieValue string) (string, error) {
+	parts := strings.SplitN(cookieValue, ".", 2)
+	if len(parts) != 2 {
+		return "", ErrInvalid
+	}
+
+	payloadRaw, sig := parts[0], parts[1]
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(payloadRaw)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	payload := string(payloadBytes)
+
+	if !hmac.Equal([]byte(sig), []byte(m.sign(payload))) {
+		return "", ErrInvalid
+	}
+
+	fields := strings.SplitN(payload, "|", 2)
+	if len(fields) != 2 {
+		return "", ErrInvalid
+	}
+	username, expiresRaw := fields[0], fields[1]
+
+	expires, err := strconv.ParseInt(expiresRaw, 10, 64)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	if time.Now().Unix() > expires {
+		return "", ErrInvalid
+	}
+
+	return username, nil
+}
+
+// FromRequest lee y valida la cookie de sesión de r.
+func (m Manager) FromRequest(r *http.Request) (string, error) {
+	c, err := r.Cookie(CookieName)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return m.Verify(c.Value)
+}
+
+func (m Manager) sign(payload string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,45 @@
+package session
+
+import "testing"
+
+func TestIssueVerifyRoundtrip(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	cookie := m.Issue("ana")
+
+	got, err := m.Verify(cookie)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got != "ana" {
+		t.Fatalf("Verify() = %q, want %q", got, "ana")
+	}
+}
+
+func TestVerifyRejectsTamperedPayload(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	cookie := m.Issue("ana")
+
+	// Alter the first character of the base64 payload ("ana|..." starts
+	// with "Y") and keep the signature intact.
+	tampered := "x" + cookie[1:]
+	if _, err := m.Verify(tampered); err != ErrInvalid {
+		t.Fatalf("Verify(tampered) error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestVerifyRejectsWrongKey(t *testing.T) {
+	issuer := NewManager([]byte("clave-uno"))
+	verifier := NewManager([]byte("clave-dos"))
+
+	cookie := issuer.Issue("ana")
+	if _, err := verifier.Verify(cookie); err != ErrInvalid {
+		t.Fatalf("Verify() error = %v, want ErrInvalid for mismatched key", err)
+	}
+}
+
+func TestVerifyRejectsMalformedValue(t *testing.T) {
+	m := NewManager([]byte("clave-de-prueba"))
+	if _, err := m.Verify("not-a-valid-cookie"); err != ErrInvalid {
+		t.Fatalf("Verify() error = %v, want ErrInvalid", err)
+	}
+}
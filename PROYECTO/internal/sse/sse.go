@@ -0,0 +1,177 @@
+// Package sse implementa un bus de eventos mínimo sobre Server-Sent Events.
+// Un Broker mantiene un canal de eventos por job y permite que uno o más
+// clientes HTTP se suscriban para recibir las actualizaciones conforme
+// ocurren, en vez de depender de fragmentos HTML inyectados en el handler.
+package sse
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event es un evento tipado del protocolo SSE. Data ya debe venir
+// serializado a JSON; Broker no conoce la forma de los payloads.
+type Event struct {
+	ID   int64
+	Name string // row_pending, row_result, row_error, progress, done
+	Data []byte
+}
+
+// HeartbeatInterval controla cada cuánto se envía un comentario SSE vacío
+// para evitar que proxies intermedios cierren la conexión por inactividad.
+const HeartbeatInterval = 15 * time.Second
+
+// CloseGracePeriod es cuánto tiempo se conserva el historial de un job ya
+// cerrado. Sin esto, un cliente que todavía no alcanzó a hacer Subscribe
+// cuando el job termina (cada vez más probable con jobs que resuelven
+// desde cache en microsegundos) se queda esperando un "done" que ya se
+// publicó y se perdió.
+const CloseGracePeriod = 30 * time.Second
+
+type subscriber struct {
+	ch     chan Event
+	lastID int64
+}
+
+// Broker reparte eventos publicados bajo un jobID a todos sus suscriptores.
+// Es seguro para uso concurrente.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[string]map[*subscriber]struct{}
+	seq  map[string]int64
+	// history guarda los últimos eventos de cada job para poder reenviarlos
+	// cuando un cliente reconecta con Last-Event-ID.
+	history map[string][]Event
+	// closed marca los jobs ya cerrados cuyo history todavía no expiró
+	// (ver CloseGracePeriod); permite que un Subscribe tardío reciba el
+	// historial completo, incluido el evento "done", en vez de colgarse
+	// esperando eventos que ya no llegarán.
+	closed map[string]struct{}
+}
+
+// NewBroker crea un Broker listo para usar.
+func NewBroker() *Broker {
+	return &Broker{
+		subs:    make(map[string]map[*subscriber]struct{}),
+		seq:     make(map[string]int64),
+		history: make(map[string][]Event),
+		closed:  make(map[string]struct{}),
+	}
+}
+
+// Subscribe registra un nuevo oyente para jobID. Si lastEventID > 0, primero
+// reenvía al canal devuelto los eventos del historial posteriores a ese ID,
+// permitiendo que un cliente que perdió la conexión retome donde se quedó.
+// unsubscribe debe llamarse cuando el cliente se desconecta.
+func (b *Broker) Subscribe(jobID string, lastEventID int64) (events <-chan Event, unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, done := b.closed[jobID]; done {
+		// El job ya terminó (y Close ya corrió) antes de que este cliente
+		// llegara a suscribirse. No hay más eventos en camino: entregamos
+		// el historial completo (incluido "done") en un canal ya cerrado
+		// para que el loop del handler lo procese y termine limpio.
+		backlog := b.history[jobID]
+		ch := make(chan Event, len(backlog))
+		for _, ev := range backlog {
+			if ev.ID > lastEventID {
+				ch <- ev
+			}
+		}
+		close(ch)
+		return ch, func() {}
+	}
+
+	// El buffer deja lugar para todo el historial a reenviar: un envío
+	// bloqueante aquí, con b.mu tomado, colgaría al Broker entero.
+	backlog := 0
+	if lastEventID > 0 {
+		for _, ev := range b.history[jobID] {
+			if ev.ID > lastEventID {
+				backlog++
+			}
+		}
+	}
+	sub := &subscriber{ch: make(chan Event, 64+backlog), lastID: lastEventID}
+	if b.subs[jobID] == nil {
+		b.subs[jobID] = make(map[*subscriber]struct{})
+	}
+	b.subs[jobID][sub] = struct{}{}
+
+	if lastEventID > 0 {
+		for _, ev := range b.history[jobID] {
+			if ev.ID > lastEventID {
+				sub.ch <- ev
+			}
+		}
+	}
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// Close pudo haber corrido (y ya cerrado sub.ch) entre que este
+		// handler dejó de leer y llamó a unsubscribe; sólo cerramos si el
+		// suscriptor sigue registrado, para no cerrar dos veces el canal.
+		if _, present := b.subs[jobID][sub]; !present {
+			return
+		}
+		delete(b.subs[jobID], sub)
+		close(sub.ch)
+	}
+}
+
+// Publish envía un evento a todos los suscriptores activos de jobID y lo
+// guarda en el historial para soportar reconexiones. El ID del evento se
+// asigna internamente de forma incremental por job.
+func (b *Broker) Publish(jobID string, name string, data []byte) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq[jobID]++
+	ev := Event{ID: b.seq[jobID], Name: name, Data: data}
+	b.history[jobID] = append(b.history[jobID], ev)
+
+	for sub := range b.subs[jobID] {
+		select {
+		case sub.ch <- ev:
+		default:
+			// Suscriptor lento: no bloqueamos al publicador.
+		}
+	}
+	return ev
+}
+
+// Close desconecta a los suscriptores activos de jobID y marca el job como
+// terminado. El historial se conserva CloseGracePeriod más, para que un
+// Subscribe tardío (el job terminó antes de que el cliente llegara a
+// conectarse) todavía pueda recibirlo completo en vez de colgarse. Debe
+// llamarse cuando un job termina y ya no se esperan más eventos.
+func (b *Broker) Close(jobID string) {
+	b.mu.Lock()
+	for sub := range b.subs[jobID] {
+		close(sub.ch)
+	}
+	delete(b.subs, jobID)
+	b.closed[jobID] = struct{}{}
+	b.mu.Unlock()
+
+	time.AfterFunc(CloseGracePeriod, func() { b.expire(jobID) })
+}
+
+// expire libera el historial de un job cerrado una vez pasado el grace
+// period.
+func (b *Broker) expire(jobID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.seq, jobID)
+	delete(b.history, jobID)
+	delete(b.closed, jobID)
+}
+
+// WriteTo escribe un Event en formato SSE estándar (id/event/data) en w.
+func (e Event) WriteTo(w interface{ Write([]byte) (int, error) }) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Name, e.Data)
+	return err
+}
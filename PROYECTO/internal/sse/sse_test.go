@@ -0,0 +1,61 @@
+package sse
+
+import "testing"
+
+func TestPublishSubscribe(t *testing.T) {
+	b := NewBroker()
+	events, unsubscribe := b.Subscribe("job-1", 0)
+	defer unsubscribe()
+
+	ev := b.Publish("job-1", "progress", []byte(`{"done":1}`))
+	got := <-events
+	if got.ID != ev.ID || got.Name != "progress" {
+		t.Fatalf("got %+v, want %+v", got, ev)
+	}
+}
+
+func TestSubscribeReplaysHistoryAfterLastEventID(t *testing.T) {
+	b := NewBroker()
+	first := b.Publish("job-1", "row_result", []byte("1"))
+	second := b.Publish("job-1", "row_result", []byte("2"))
+
+	events, unsubscribe := b.Subscribe("job-1", first.ID)
+	defer unsubscribe()
+
+	got := <-events
+	if got.ID != second.ID {
+		t.Fatalf("got event %d, want only events after lastEventID (%d)", got.ID, first.ID)
+	}
+}
+
+func TestCloseDisconnectsActiveSubscribers(t *testing.T) {
+	b := NewBroker()
+	events, unsubscribe := b.Subscribe("job-1", 0)
+	defer unsubscribe()
+
+	b.Close("job-1")
+
+	if _, ok := <-events; ok {
+		t.Fatal("channel should be closed after Close")
+	}
+}
+
+func TestSubscribeAfterCloseReplaysBacklogInsteadOfHanging(t *testing.T) {
+	b := NewBroker()
+	b.Publish("job-1", "row_result", []byte("1"))
+	done := b.Publish("job-1", "done", []byte("{}"))
+	b.Close("job-1")
+
+	// A client that only reaches Subscribe after the job already finished
+	// must still see the backlog (and "done"), not block forever.
+	events, unsubscribe := b.Subscribe("job-1", 0)
+	defer unsubscribe()
+
+	var last Event
+	for ev := range events {
+		last = ev
+	}
+	if last.ID != done.ID || last.Name != "done" {
+		t.Fatalf("last replayed event = %+v, want the done event %+v", last, done)
+	}
+}
@@ -0,0 +1,131 @@
+// Package tokencrypt cifra en reposo el token de la API de consulta
+// (token.txt) con AES-GCM, en vez de guardarlo en texto plano como hasta
+// ahora. No hay tokens por usuario: el token es global (ver
+// internal/authstore.User).
+package tokencrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeySize es el tamaño de clave AES-256 usado para cifrar/descifrar.
+const KeySize = 32
+
+// scryptN, scryptR y scryptP son los parámetros recomendados por
+// golang.org/x/crypto/scrypt para derivar claves interactivas (128·N·r
+// bytes ≈ 32 MiB).
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrNoKeySource se devuelve cuando no hay ni MASTER_KEY ni
+// MASTER_PASSPHRASE configurados: no hay forma segura de cifrar nada.
+var ErrNoKeySource = errors.New("tokencrypt: falta MASTER_KEY o MASTER_PASSPHRASE en el entorno")
+
+// saltFile guarda la sal usada para derivar la clave desde
+// MASTER_PASSPHRASE. Vive junto al resto de archivos de estado de la app.
+const saltFile = "master.salt"
+
+// MasterKey resuelve la clave de cifrado de la app: usa MASTER_KEY si está
+// presente (se hashea con SHA-256 para normalizar su longitud a 32 bytes),
+// o deriva una con scrypt a partir de MASTER_PASSPHRASE y una sal
+// persistida en disco. Sin ninguna de las dos, devuelve ErrNoKeySource.
+func MasterKey() ([]byte, error) {
+	if raw := os.Getenv("MASTER_KEY"); raw != "" {
+		sum := sha256.Sum256([]byte(raw))
+		return sum[:], nil
+	}
+
+	passphrase := os.Getenv("MASTER_PASSPHRASE")
+	if passphrase == "" {
+		return nil, ErrNoKeySource
+	}
+
+	salt, err := loadOrCreateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("tokencrypt: preparando sal: %w", err)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("tokencrypt: derivando clave: %w", err)
+	}
+	return key, nil
+}
+
+func loadOrCreateSalt() ([]byte, error) {
+	if salt, err := os.ReadFile(saltFile); err == nil {
+		return salt, nil
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(saltFile, salt, 0600); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Encrypt cifra plaintext con AES-GCM y devuelve un envoltorio
+// nonce||ciphertext codificado en base64, listo para guardar en un
+// archivo de texto o una columna TEXT.
+func Encrypt(key []byte, plaintext string) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: creando cifrador: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: creando GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("tokencrypt: generando nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt revierte Encrypt. Devuelve error si el envoltorio fue alterado
+// o fue cifrado con otra clave.
+func Decrypt(key []byte, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: decodificando base64: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: creando cifrador: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: creando GCM: %w", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("tokencrypt: texto cifrado demasiado corto")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("tokencrypt: descifrando: %w", err)
+	}
+	return string(plaintext), nil
+}
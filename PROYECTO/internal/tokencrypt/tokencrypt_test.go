@@ -0,0 +1,64 @@
+package tokencrypt
+
+import "testing"
+
+func testKey() []byte {
+	return []byte("0123456789abcdef0123456789abcdef") // 32 bytes
+}
+
+func TestEncryptDecryptRoundtrip(t *testing.T) {
+	key := testKey()
+	ciphertext, err := Encrypt(key, "super-secret-token")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	got, err := Decrypt(key, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if got != "super-secret-token" {
+		t.Fatalf("Decrypt() = %q, want %q", got, "super-secret-token")
+	}
+}
+
+func TestDecryptRejectsWrongKey(t *testing.T) {
+	ciphertext, err := Encrypt(testKey(), "super-secret-token")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	wrongKey := []byte("zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz")
+	if _, err := Decrypt(wrongKey, ciphertext); err == nil {
+		t.Fatal("Decrypt() with the wrong key should fail")
+	}
+}
+
+func TestDecryptRejectsTamperedCiphertext(t *testing.T) {
+	key := testKey()
+	ciphertext, err := Encrypt(key, "super-secret-token")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tampered := []byte(ciphertext)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := Decrypt(key, string(tampered)); err == nil {
+		t.Fatal("Decrypt() of tampered ciphertext should fail")
+	}
+}
+
+func TestEncryptProducesDistinctCiphertextsForSamePlaintext(t *testing.T) {
+	key := testKey()
+	a, err := Encrypt(key, "same-token")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	b, err := Encrypt(key, "same-token")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if a == b {
+		t.Fatal("Encrypt() should use a fresh random nonce each call")
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"proyecto/internal/jobstore"
+	"proyecto/internal/namematch"
+)
+
+// jobResponse es la forma pública de un jobstore.Job en la API REST.
+type jobResponse struct {
+	JobID  string `json:"job_id"`
+	Total  int    `json:"total"`
+	Done   int    `json:"done"`
+	Status string `json:"status"`
+}
+
+func toJobResponse(j jobstore.Job) jobResponse {
+	return jobResponse{JobID: j.ID, Total: j.Total, Done: j.Done, Status: j.Status}
+}
+
+// handleAPIJobsCollection atiende POST /api/jobs: crea un job a partir de
+// un lote en JSON (`{"rows":[{"dni":...,"nombre":...}]}`) o CSV
+// (`dni,nombre,paterno,materno` por línea, según Content-Type).
+func handleAPIJobsCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rows, matchCfg, err := decodeBatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(rows) == 0 {
+		http.Error(w, "El lote no tiene filas", http.StatusBadRequest)
+		return
+	}
+
+	jobID, err := createJob(r.Context(), rows, matchCfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"job_id":     jobID,
+		"status_url": "/api/jobs/" + jobID,
+	})
+}
+
+type batchRow struct {
+	DNI     string `json:"dni"`
+	Nombre  string `json:"nombre"`
+	Paterno string `json:"paterno"`
+	Materno string `json:"materno"`
+}
+
+// decodeBatch interpreta el cuerpo del POST como JSON o CSV según el
+// Content-Type de la petición. El CSV no trae forma de ajustar namematch,
+// así que siempre usa los valores por defecto.
+func decodeBatch(r *http.Request) ([]ComparisonRow, namematch.Config, error) {
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		rows, err := decodeBatchCSV(r.Body)
+		return rows, namematch.DefaultConfig(), err
+	}
+	return decodeBatchJSON(r.Body)
+}
+
+// matchConfig es la parte opcional del payload JSON que ajusta los
+// umbrales de namematch para este job; un campo en cero usa el default.
+type matchConfig struct {
+	MaxEditDistance      int     `json:"max_edit_distance"`
+	JaroWinklerThreshold float64 `json:"jw_threshold"`
+}
+
+func decodeBatchJSON(body io.Reader) ([]ComparisonRow, namematch.Config, error) {
+	var payload struct {
+		Rows        []batchRow  `json:"rows"`
+		MatchConfig matchConfig `json:"match_config"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, namematch.Config{}, errors.New("JSON de entrada inválido: " + err.Error())
+	}
+	rows := make([]ComparisonRow, len(payload.Rows))
+	for i, br := range payload.Rows {
+		rows[i] = ComparisonRow{ID: i, DNI: strings.TrimSpace(br.DNI),
+			NombreInput: br.Nombre, PaternoInput: br.Paterno, MaternoInput: br.Materno, IsPending: true}
+	}
+	cfg := namematch.Config{MaxEditDistance: payload.MatchConfig.MaxEditDistance, JaroWinklerThreshold: payload.MatchConfig.JaroWinklerThreshold}
+	return rows, cfg, nil
+}
+
+func decodeBatchCSV(body io.Reader) ([]ComparisonRow, error) {
+	records, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		return nil, errors.New("CSV de entrada inválido: " + err.Error())
+	}
+	var rows []ComparisonRow
+	for _, rec := range records {
+		if len(rec) == 0 || strings.TrimSpace(rec[0]) == "" {
+			continue
+		}
+		row := ComparisonRow{ID: len(rows), DNI: strings.TrimSpace(rec[0]), IsPending: true}
+		if len(rec) > 1 {
+			row.NombreInput = strings.TrimSpace(rec[1])
+		}
+		if len(rec) > 2 {
+			row.PaternoInput = strings.TrimSpace(rec[2])
+		}
+		if len(rec) > 3 {
+			row.MaternoInput = strings.TrimSpace(rec[3])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// handleAPIJobsItem enruta las operaciones sobre un job concreto:
+//
+//	GET    /api/jobs/{id}
+//	GET    /api/jobs/{id}/rows
+//	GET    /api/jobs/{id}/export
+//	DELETE /api/jobs/{id}
+func handleAPIJobsItem(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	parts := strings.SplitN(path, "/", 2)
+	jobID := parts[0]
+	if jobID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		handleGetJob(w, r, jobID)
+	case sub == "" && r.Method == http.MethodDelete:
+		handleDeleteJob(w, r, jobID)
+	case sub == "rows" && r.Method == http.MethodGet:
+		handleListRows(w, r, jobID)
+	case sub == "export" && r.Method == http.MethodGet:
+		handleExportJob(w, r, jobID)
+	default:
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleGetJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	j, err := store.GetJob(r.Context(), jobID)
+	if errors.Is(err, jobstore.ErrNotFound) {
+		http.Error(w, "Job no encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(toJobResponse(j))
+}
+
+func handleDeleteJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	if err := store.DeleteJob(r.Context(), jobID); errors.Is(err, jobstore.ErrNotFound) {
+		http.Error(w, "Job no encontrado", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListRows pagina los resultados: ?status=mismatch|error|ok&offset=&limit=
+func handleListRows(w http.ResponseWriter, r *http.Request, jobID string) {
+	status := r.URL.Query().Get("status")
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := store.ListRows(r.Context(), jobID, status, offset, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rows":   rows,
+		"offset": offset,
+		"limit":  limit,
+	})
+}
+
+// handleExportJob vuelca todas las filas de un job en el formato pedido
+// por ?format=csv|xlsx|json (json por defecto).
+func handleExportJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	format := r.URL.Query().Get("format")
+
+	rows, err := store.ListRows(r.Context(), jobID, "", 0, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, contentType, err := exportRows(format, rows)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="job-`+jobID+`.`+format+`"`)
+	w.Write(data)
+}
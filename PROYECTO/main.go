@@ -2,16 +2,32 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"proyecto/internal/authstore"
+	"proyecto/internal/csrf"
+	"proyecto/internal/jobstore"
+	"proyecto/internal/metrics"
+	"proyecto/internal/namematch"
+	"proyecto/internal/provider"
+	"proyecto/internal/ratelimit"
+	"proyecto/internal/session"
+	"proyecto/internal/sse"
+	"proyecto/internal/tokencrypt"
 )
 
 // Configuración
@@ -27,16 +43,12 @@ const (
 
 	ReadTimeout  = 0 // Desactivamos timeout global de lectura para permitir streaming largo
 	WriteTimeout = 0 // Desactivamos timeout de escritura para streaming
+
+	DBFile    = "jobs.db"
+	UsersFile = "users.db"
 )
 
 // Estructuras de Datos
-type APIResponse struct {
-	FirstName      string `json:"first_name"`
-	FirstLastName  string `json:"first_last_name"`
-	SecondLastName string `json:"second_last_name"`
-	Message        string `json:"message"`
-}
-
 type ComparisonRow struct {
 	ID           int
 	DNI          string
@@ -51,6 +63,8 @@ type ComparisonRow struct {
 	MatchNombre  bool
 	MatchPaterno bool
 	MatchMaterno bool
+	MatchScore   float64
+	MatchLevel   namematch.Level
 	HasError     bool
 	ErrorMessage string
 	IsPending    bool // Para la vista inicial
@@ -58,6 +72,62 @@ type ComparisonRow struct {
 
 var templates *template.Template
 
+// broker reparte los eventos de progreso de cada job a los clientes SSE
+// suscritos. Ver internal/sse para el protocolo.
+var broker = sse.NewBroker()
+
+// store persiste los jobs y sus filas en SQLite, para que un lote sobreviva
+// a un reinicio del proceso y pueda consultarse por la API REST.
+var store *jobstore.Store
+
+// dniProvider resuelve los datos RENIEC de un DNI. Ver setupProvider en
+// providers_setup.go para cómo se arma (Chain + Cache, o el modo legado
+// de un solo proveedor).
+var dniProvider provider.Provider
+
+// users persiste las cuentas de operador (ver auth.go y internal/authstore).
+var users *authstore.Store
+
+// masterKey cifra token.txt (internal/tokencrypt) y firma sesiones y
+// tokens CSRF; se resuelve una sola vez en main(). No hay tokens por
+// usuario que cifrar: el token de API es global (ver authstore.User).
+var masterKey []byte
+
+// sessionMgr firma y valida las cookies de sesión emitidas por /login.
+var sessionMgr session.Manager
+
+// csrfMgr firma y valida los tokens anti-CSRF de los formularios POST.
+var csrfMgr csrf.Manager
+
+// workerPool reemplaza al viejo semáforo de tamaño fijo (MaxWorkers): crece
+// o encoge según la p95 de latencia observada en las consultas, entre 1 y
+// MaxPoolWorkers en paralelo.
+const MaxPoolWorkers = 20
+
+var workerPool = ratelimit.NewPool(MaxWorkers, 1, MaxPoolWorkers)
+
+// logger es el logger estructurado de toda la app. Nunca se le pasa el DNI
+// en crudo (ver dniHash): es PII y no debe terminar en los logs.
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// dniHash resume un DNI a un hash corto apto para logs, sin exponer el
+// valor real.
+func dniHash(dni string) string {
+	sum := sha256.Sum256([]byte(dni))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// newJobID genera un identificador de job aleatorio y corto.
+func newJobID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// Degradación razonable: timestamp con nanosegundos sigue siendo
+		// único en la práctica para un solo proceso.
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
 func init() {
 	var err error
 	// Funciones auxiliares para los templates
@@ -68,14 +138,61 @@ func init() {
 	}
 	templates, err = template.New("").Funcs(funcMap).ParseGlob("templates/*.html")
 	if err != nil {
-		log.Fatalf("Error cargando templates: %v", err)
+		logger.Error("cargando templates", "err", err)
+		os.Exit(1)
 	}
 }
 
 func main() {
+	var err error
+	store, err = jobstore.Open(DBFile)
+	if err != nil {
+		logger.Error("abriendo la base de jobs", "err", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	// masterKey tiene que estar resuelta antes de setupProvider: el modo
+	// legado descifra token.txt con ella (ver getToken).
+	masterKey, err = tokencrypt.MasterKey()
+	if err != nil {
+		logger.Error("resolviendo clave maestra", "err", err)
+		os.Exit(1)
+	}
+	sessionMgr = session.NewManager(masterKey)
+	csrfMgr = csrf.NewManager(masterKey)
+
+	dniProvider, err = setupProvider()
+	if err != nil {
+		logger.Error("configurando proveedores DNI", "err", err)
+		os.Exit(1)
+	}
+	if closer, ok := dniProvider.(interface{ Close() }); ok {
+		defer closer.Close()
+	}
+
+	users, err = authstore.Open(UsersFile)
+	if err != nil {
+		logger.Error("abriendo la base de usuarios", "err", err)
+		os.Exit(1)
+	}
+	defer users.Close()
+
+	http.HandleFunc("/login", handleLogin)
+	http.HandleFunc("/logout", handleLogout)
+	http.HandleFunc("/register", handleRegister)
+
 	http.HandleFunc("/", authMiddleware(handleIndex))
-	http.HandleFunc("/guardar-token", handleSaveToken)
-	http.HandleFunc("/consultar", authMiddleware(handleConsultar))
+	http.HandleFunc("/guardar-token", authMiddleware(requireCSRF(handleSaveToken)))
+	http.HandleFunc("/consultar", authMiddleware(requireCSRF(handleConsultar)))
+	http.HandleFunc("/consultar/stream", authMiddleware(handleConsultarStream))
+
+	http.HandleFunc("/api/jobs", authMiddleware(requireCSRF(handleAPIJobsCollection)))
+	http.HandleFunc("/api/jobs/", authMiddleware(requireCSRF(handleAPIJobsItem)))
+
+	http.HandleFunc("/metrics", handleMetrics)
+	http.HandleFunc("/healthz", handleHealthz)
+	http.HandleFunc("/readyz", handleReadyz)
 
 	// --- CAMBIO PARA RENDER Y NUBE ---
 	// Render te asigna un puerto en la variable de entorno PORT.
@@ -91,29 +208,51 @@ func main() {
 		Handler: nil,
 	}
 
-	fmt.Printf("🚀 Servidor corriendo en puerto :%s\n", port)
-	fmt.Printf("⚡ MODO STREAMING ACTIVADO: %d hilos. Actualización en tiempo real.\n", MaxWorkers)
+	logger.Info("servidor iniciado", "port", port, "worker_pool_initial", MaxWorkers)
 
 	if err := server.ListenAndServe(); err != nil {
-		log.Fatal(err)
+		logger.Error("servidor detenido", "err", err)
+		os.Exit(1)
 	}
 }
 
 // --- Middleware & Helpers ---
 
+// getToken descifra el token legado guardado en TokenFile (modo de un
+// solo proveedor, ver setupProvider). Vacío si no existe o no se puede
+// descifrar (p. ej. quedó de antes de cifrar token.txt).
 func getToken() string {
 	content, err := os.ReadFile(TokenFile)
 	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(string(content))
+	token, err := tokencrypt.Decrypt(masterKey, strings.TrimSpace(string(content)))
+	if err != nil {
+		logger.Warn("descifrando token.txt", "err", err)
+		return ""
+	}
+	return token
 }
 
+// authMiddleware exige una sesión válida (ver /login) antes de dejar
+// pasar a next. Si hay sesión pero dniProvider no llegó a configurarse,
+// muestra el formulario para cargar un token (modo legado de un solo
+// proveedor; setupProvider ya falla el arranque si no hay ni
+// providers.yaml ni token.txt, así que esto no debería dispararse en
+// un proceso corriendo, pero gatear en dniProvider en vez de getToken()
+// evita que los despliegues con providers.yaml — que nunca escriben
+// token.txt — vean este formulario en cada request).
 func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := getToken()
-		if token == "" {
-			if err := templates.ExecuteTemplate(w, "formulario_token.html", nil); err != nil {
+		if _, err := sessionMgr.FromRequest(r); err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		if dniProvider == nil {
+			if err := templates.ExecuteTemplate(w, "formulario_token.html", map[string]string{
+				"CSRFToken": csrfTokenForRequest(r),
+			}); err != nil {
 				http.Error(w, "Error interno de template", http.StatusInternalServerError)
 			}
 			return
@@ -122,6 +261,45 @@ func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// requireCSRF exige que las peticiones que mutan estado (todo salvo GET,
+// HEAD y OPTIONS) traigan un csrf_token válido para la sesión activa,
+// como cabecera X-CSRF-Token o campo de formulario. Debe envolver a next
+// por dentro de authMiddleware, que ya garantiza que hay sesión.
+func requireCSRF(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		username, err := sessionMgr.FromRequest(r)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		token := r.Header.Get("X-CSRF-Token")
+		if token == "" {
+			token = r.FormValue(csrf.FieldName)
+		}
+		if !csrfMgr.Valid(username, token) {
+			http.Error(w, "Token CSRF inválido o ausente", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// csrfTokenForRequest devuelve el token CSRF de la sesión de r, o "" si
+// no hay sesión (no debería pasar detrás de authMiddleware).
+func csrfTokenForRequest(r *http.Request) string {
+	username, err := sessionMgr.FromRequest(r)
+	if err != nil {
+		return ""
+	}
+	return csrfMgr.Token(username)
+}
+
 // --- Handlers ---
 
 func handleIndex(w http.ResponseWriter, r *http.Request) {
@@ -129,9 +307,13 @@ func handleIndex(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
 		return
 	}
-	templates.ExecuteTemplate(w, "formulario_consulta.html", nil)
+	templates.ExecuteTemplate(w, "formulario_consulta.html", map[string]string{
+		"CSRFToken": csrfTokenForRequest(r),
+	})
 }
 
+// handleSaveToken cifra el token recibido con AES-GCM antes de
+// guardarlo: token.txt ya no queda en texto plano en disco.
 func handleSaveToken(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
@@ -142,39 +324,80 @@ func handleSaveToken(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
-	os.WriteFile(TokenFile, []byte(token), 0644)
-	http.Redirect(w, r, "/", http.StatusSeeOther)
-}
 
-// handleConsultar usa Streaming HTML para actualizaciones en tiempo real
-func handleConsultar(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+	encrypted, err := tokencrypt.Encrypt(masterKey, token)
+	if err != nil {
+		http.Error(w, "cifrando token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	// 1. Setup de Streaming
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "Streaming no soportado", http.StatusInternalServerError)
+	if err := os.WriteFile(TokenFile, []byte(encrypted), 0600); err != nil {
+		http.Error(w, "guardando token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
 
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.Header().Set("Transfer-Encoding", "chunked")
-	w.Header().Set("X-Accel-Buffering", "no") // Deshabilitar buffering en Nginx/Proxies si los hubiera
+// rowEvent es el payload JSON publicado para cada fila procesada. El cliente
+// construye el <tr> a partir de estos datos; el servidor ya no genera HTML.
+type rowEvent struct {
+	ID           int    `json:"id"`
+	DNI          string `json:"dni"`
+	NombreInput  string `json:"nombre_input"`
+	PaternoInput string `json:"paterno_input"`
+	MaternoInput string `json:"materno_input"`
+	NombreAPI    string `json:"nombre_api"`
+	PaternoAPI   string `json:"paterno_api"`
+	MaternoAPI   string `json:"materno_api"`
+	MatchNombre  bool    `json:"match_nombre"`
+	MatchPaterno bool    `json:"match_paterno"`
+	MatchMaterno bool    `json:"match_materno"`
+	MatchScore   float64 `json:"match_score"`
+	MatchLevel   string  `json:"match_level"`
+	HasError     bool    `json:"has_error"`
+	ErrorMessage string  `json:"error_message,omitempty"`
+}
 
-	// 2. Procesar Inputs
-	rawDnis := r.FormValue("dnis")
-	rawNombres := r.FormValue("nombres")
-	token := getToken()
+type progressEvent struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
 
+func toRowEvent(r ComparisonRow) rowEvent {
+	return rowEvent{
+		ID:           r.ID,
+		DNI:          r.DNI,
+		NombreInput:  r.NombreInput,
+		PaternoInput: r.PaternoInput,
+		MaternoInput: r.MaternoInput,
+		NombreAPI:    r.NombreAPI,
+		PaternoAPI:   r.PaternoAPI,
+		MaternoAPI:   r.MaternoAPI,
+		MatchNombre:  r.MatchNombre,
+		MatchPaterno: r.MatchPaterno,
+		MatchMaterno: r.MatchMaterno,
+		MatchScore:   r.MatchScore,
+		MatchLevel:   r.MatchLevel.String(),
+		HasError:     r.HasError,
+		ErrorMessage: r.ErrorMessage,
+	}
+}
+
+func publishJSON(jobID, eventName string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("serializando evento sse", "job_id", jobID, "event", eventName, "err", err)
+		return
+	}
+	broker.Publish(jobID, eventName, data)
+}
+
+// parseRows interpreta las dos áreas de texto del formulario (DNIs y
+// nombres separados por tab) y arma las filas iniciales del job.
+func parseRows(rawDnis, rawNombres string) []ComparisonRow {
 	scannerDNI := bufio.NewScanner(strings.NewReader(rawDnis))
 	scannerNames := bufio.NewScanner(strings.NewReader(rawNombres))
 
-	var initialRows []ComparisonRow
-
-	// Preparamos las filas iniciales (Estado "Pendiente")
+	var rows []ComparisonRow
 	idCounter := 0
 	for scannerDNI.Scan() {
 		dni := strings.TrimSpace(scannerDNI.Text())
@@ -199,103 +422,154 @@ func handleConsultar(w http.ResponseWriter, r *http.Request) {
 			matIn = strings.TrimSpace(parts[2])
 		}
 
-		initialRows = append(initialRows, ComparisonRow{
+		rows = append(rows, ComparisonRow{
 			ID:           idCounter,
 			DNI:          dni,
 			NombreInput:  nombreIn,
 			PaternoInput: patIn,
 			MaternoInput: matIn,
-			IsPending:    true, // Bandera para mostrar spinner
+			IsPending:    true,
 		})
 		idCounter++
 	}
+	return rows
+}
 
-	// 3. Renderizar la página inicial (Tabla con spinners)
-	data := map[string]interface{}{
-		"Resultados": initialRows,
-		"Total":      len(initialRows),
+// handleConsultar crea un job a partir del lote recibido y responde de
+// inmediato con su job_id; el procesamiento real ocurre en segundo plano y
+// se sigue por /consultar/stream (SSE).
+func handleConsultar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
 	}
-	if err := templates.ExecuteTemplate(w, "tabla_resultados.html", data); err != nil {
-		fmt.Printf("Error template: %v\n", err)
+
+	rawDnis := r.FormValue("dnis")
+	rawNombres := r.FormValue("nombres")
+
+	initialRows := parseRows(rawDnis, rawNombres)
+	cfg := matchConfigFromForm(r)
+
+	jobID, err := createJob(r.Context(), initialRows, cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	flusher.Flush() // Enviar al navegador inmediatamente
 
-	// 4. Iniciar Procesamiento en Background
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job_id":     jobID,
+		"total":      len(initialRows),
+		"status_url": "/api/jobs/" + jobID,
+	})
+}
+
+// matchConfigFromForm arma la Config de namematch a partir de los campos
+// opcionales del formulario clásico, cayendo a los valores por defecto
+// cuando no vienen (o no son numéricos).
+func matchConfigFromForm(r *http.Request) namematch.Config {
+	cfg := namematch.DefaultConfig()
+	if v, err := strconv.Atoi(r.FormValue("max_edit_distance")); err == nil {
+		cfg.MaxEditDistance = v
+	}
+	if v, err := strconv.ParseFloat(r.FormValue("jw_threshold"), 64); err == nil {
+		cfg.JaroWinklerThreshold = v
+	}
+	return cfg
+}
+
+// createJob persiste un job nuevo y lanza su procesamiento en segundo
+// plano. Lo usan tanto el formulario clásico (/consultar) como la API REST
+// de jobs (/api/jobs). matchCfg ajusta los umbrales de namematch para este
+// job en particular; pasar namematch.Config{} usa los valores por defecto.
+func createJob(ctx context.Context, rows []ComparisonRow, matchCfg namematch.Config) (string, error) {
+	jobID := newJobID()
+
+	storeRows := make([]jobstore.Row, len(rows))
+	for i, row := range rows {
+		storeRows[i] = jobstore.Row{ID: row.ID, DNI: row.DNI,
+			NombreInput: row.NombreInput, PaternoInput: row.PaternoInput, MaternoInput: row.MaternoInput}
+	}
+	if _, err := store.CreateJob(ctx, jobID, storeRows); err != nil {
+		return "", fmt.Errorf("creando job: %w", err)
+	}
+
+	go runJob(jobID, rows, namematch.NewPipeline(matchCfg))
+	return jobID, nil
+}
+
+// runJob consulta dniProvider para cada fila del lote con concurrencia
+// acotada, persiste cada resultado en el store y publica un evento SSE
+// por cada avance hasta emitir "done". pipeline decide, por campo, si el
+// nombre ingresado y el de la API coinciden de forma exacta, normalizada
+// o difusa.
+func runJob(jobID string, rows []ComparisonRow, pipeline namematch.Pipeline) {
+	start := time.Now()
+	metrics.ActiveJobs.Inc()
+	defer func() {
+		metrics.ActiveJobs.Dec()
+		metrics.JobDuration.Observe(time.Since(start).Seconds())
+	}()
+	defer broker.Close(jobID)
+
+	for _, row := range rows {
+		publishJSON(jobID, "row_pending", toRowEvent(row))
+	}
+
 	var wg sync.WaitGroup
 	resultsChan := make(chan ComparisonRow)
-	sem := make(chan struct{}, MaxWorkers)
-	client := &http.Client{Timeout: 30 * time.Second}
 
-	// Productor (Workers)
 	go func() {
-		for _, row := range initialRows {
+		for _, row := range rows {
 			wg.Add(1)
-
-			// Pausa ligera entre lanzamientos
 			time.Sleep(RequestDelay)
 
 			go func(r ComparisonRow) {
 				defer wg.Done()
-				sem <- struct{}{} // Adquirir semáforo
-				defer func() { <-sem }()
-
-				// --- Retry Logic ---
-				var resp *http.Response
+				workerPool.Acquire()
+				metrics.WorkerInflight.Set(float64(workerPool.Inflight()))
+				attemptStart := time.Now()
+				defer func() {
+					workerPool.Release(time.Since(attemptStart))
+					metrics.WorkerInflight.Set(float64(workerPool.Inflight()))
+				}()
+
+				ctx := context.Background()
+				var person provider.Person
 				var err error
-				success := false
 
 				for attempt := 0; attempt < MaxRetries; attempt++ {
-					req, _ := http.NewRequest("GET", ApiBaseURL+r.DNI, nil)
-					req.Header.Set("Authorization", "Bearer "+token)
-					req.Header.Set("User-Agent", "Go-Validator/3.0")
-
-					resp, err = client.Do(req)
-
-					if err == nil {
-						if resp.StatusCode == 429 {
-							resp.Body.Close()
-							// Reintento específico para 429
-							time.Sleep(time.Duration(2+attempt) * time.Second)
-							continue
-						}
-						success = true
+					person, err = dniProvider.Lookup(ctx, r.DNI)
+					if err == nil || errors.Is(err, provider.ErrNotFound) {
 						break
-					} else {
-						time.Sleep(1 * time.Second)
 					}
+					logger.Warn("consulta DNI falló", "job_id", jobID, "dni_hash", dniHash(r.DNI), "attempt", attempt, "err", err)
+					if errors.Is(err, provider.ErrRateLimited) {
+						time.Sleep(time.Duration(2+attempt) * time.Second)
+						continue
+					}
+					time.Sleep(1 * time.Second)
 				}
 
-				r.IsPending = false // Ya no está pendiente
+				r.IsPending = false
 
-				if !success || err != nil {
+				if err != nil {
 					r.HasError = true
-					r.ErrorMessage = "Error Red"
-					if err == nil && resp != nil {
-						r.ErrorMessage = fmt.Sprintf("HTTP %d", resp.StatusCode)
-					}
+					r.ErrorMessage = err.Error()
 				} else {
-					defer resp.Body.Close()
-					bodyBytes, _ := io.ReadAll(resp.Body)
-
-					if resp.StatusCode == 200 {
-						var apiData APIResponse
-						if json.Unmarshal(bodyBytes, &apiData) == nil {
-							r.NombreAPI = apiData.FirstName
-							r.PaternoAPI = apiData.FirstLastName
-							r.MaternoAPI = apiData.SecondLastName
-
-							r.MatchNombre = strings.EqualFold(r.NombreInput, r.NombreAPI)
-							r.MatchPaterno = strings.EqualFold(r.PaternoInput, r.PaternoAPI)
-							r.MatchMaterno = strings.EqualFold(r.MaternoInput, r.MaternoAPI)
-						} else {
-							r.HasError = true
-							r.ErrorMessage = "JSON Error"
-						}
-					} else {
-						r.HasError = true
-						r.ErrorMessage = fmt.Sprintf("HTTP %d", resp.StatusCode)
-					}
+					r.NombreAPI = person.Nombre
+					r.PaternoAPI = person.Paterno
+					r.MaternoAPI = person.Materno
+
+					resNombre := pipeline.Compare(r.NombreInput, r.NombreAPI)
+					resPaterno := pipeline.Compare(r.PaternoInput, r.PaternoAPI)
+					resMaterno := pipeline.Compare(r.MaternoInput, r.MaternoAPI)
+
+					r.MatchNombre = resNombre.Level != namematch.Mismatch
+					r.MatchPaterno = resPaterno.Level != namematch.Mismatch
+					r.MatchMaterno = resMaterno.Level != namematch.Mismatch
+					r.MatchLevel = worstLevel(resNombre.Level, resPaterno.Level, resMaterno.Level)
+					r.MatchScore = minScore(resNombre.Score, resPaterno.Score, resMaterno.Score)
 				}
 				resultsChan <- r
 			}(row)
@@ -304,82 +578,129 @@ func handleConsultar(w http.ResponseWriter, r *http.Request) {
 		close(resultsChan)
 	}()
 
-	// 5. Consumidor (Streaming de Scripts)
-	// Recibimos resultados conforme llegan y enviamos <script> para actualizar el DOM
+	done := 0
 	for res := range resultsChan {
-		htmlContent := generateRowHTML(res)
-		// Enviamos un pequeño script que busca el ID de la fila y reemplaza su contenido
-		// y actualiza las clases CSS según el resultado
-		fmt.Fprintf(w, "<script>updateRow(%d, `%s`, %t);</script>\n", res.ID, htmlContent, res.HasError)
-		flusher.Flush()
+		done++
+		eventName := "row_result"
+		if res.HasError {
+			eventName = "row_error"
+		}
+		publishJSON(jobID, eventName, toRowEvent(res))
+		publishJSON(jobID, "progress", progressEvent{Done: done, Total: len(rows)})
+
+		if err := store.UpdateRow(context.Background(), jobstore.Row{
+			ID: res.ID, JobID: jobID,
+			NombreAPI: res.NombreAPI, PaternoAPI: res.PaternoAPI, MaternoAPI: res.MaternoAPI,
+			Status:       rowStatus(res),
+			ErrorMessage: res.ErrorMessage,
+			MatchScore:   res.MatchScore,
+			MatchLevel:   res.MatchLevel.String(),
+		}); err != nil {
+			logger.Error("guardando fila en jobstore", "job_id", jobID, "row_id", res.ID, "err", err)
+		}
 	}
+
+	publishJSON(jobID, "done", progressEvent{Done: done, Total: len(rows)})
 }
 
-// generateRowHTML crea el HTML interno de la fila (TDs)
-func generateRowHTML(r ComparisonRow) string {
-	// Clases CSS
-	matchClass := "match-ok"
-	failClass := "match-fail"
+// worstLevel devuelve el peor (más bajo) de los niveles de coincidencia de
+// las tres columnas de nombre, que es el que se muestra a nivel de fila.
+func worstLevel(levels ...namematch.Level) namematch.Level {
+	worst := namematch.Exact
+	for _, l := range levels {
+		if l < worst {
+			worst = l
+		}
+	}
+	return worst
+}
 
-	// Helper para clases condicionales
-	getClass := func(match bool) string {
-		if match {
-			return matchClass
+// minScore devuelve el menor de los scores de las tres columnas de
+// nombre: la fila es tan buena como su peor campo.
+func minScore(scores ...float64) float64 {
+	min := scores[0]
+	for _, s := range scores[1:] {
+		if s < min {
+			min = s
 		}
-		return failClass
-	}
-
-	// Construimos el HTML manualmente para inyectarlo vía JS
-	// Usamos backticks en JS, así que cuidado con escaparlos si fuera necesario,
-	// pero aquí es HTML simple.
-
-	errorBadge := ""
-	if r.HasError {
-		errorBadge = fmt.Sprintf(`<span class="block text-[10px] text-red-500 font-bold">%s</span>`, r.ErrorMessage)
-	}
-
-	// Botón copiar individual
-	copyBtn := fmt.Sprintf(`
-        <button onclick="copyRow(%d)" class="ml-2 text-slate-400 hover:text-blue-600 transition-colors p-1 rounded-full hover:bg-blue-50" title="Copiar datos RENIEC">
-            <svg xmlns="http://www.w3.org/2000/svg" class="h-4 w-4" fill="none" viewBox="0 0 24 24" stroke="currentColor">
-                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 16H6a2 2 0 01-2-2V6a2 2 0 012-2h8a2 2 0 012 2v2m-6 12h8a2 2 0 002-2v-8a2 2 0 00-2-2h-8a2 2 0 00-2 2v8a2 2 0 002 2z" />
-            </svg>
-        </button>
-    `, r.ID)
-
-	if r.HasError {
-		copyBtn = "" // No mostrar botón copiar si hay error
-	}
-
-	html := fmt.Sprintf(`
-        <td class="px-4 py-4 whitespace-nowrap text-sm font-mono text-slate-900 border-r border-slate-100">
-            %s %s
-        </td>
-        
-        <!-- Input Data -->
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-center border-l %s">%s</td>
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-center %s">%s</td>
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-center border-r border-slate-200 %s">%s</td>
-
-        <!-- API Data (Con IDs para copiar) -->
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-slate-600 text-center border-l border-slate-100 font-medium">
-            <span id="nom-%d">%s</span>
-        </td>
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-slate-600 text-center font-medium">
-            <span id="pat-%d">%s</span>
-        </td>
-        <td class="px-4 py-3 whitespace-nowrap text-sm text-slate-600 text-center border-r border-slate-100 font-medium flex items-center justify-center gap-2">
-            <span id="mat-%d">%s</span>
-            %s
-        </td>
-    `,
-		r.DNI, errorBadge,
-		getClass(r.MatchNombre), r.NombreInput,
-		getClass(r.MatchPaterno), r.PaternoInput,
-		getClass(r.MatchMaterno), r.MaternoInput,
-		r.ID, r.NombreAPI,
-		r.ID, r.PaternoAPI,
-		r.ID, r.MaternoAPI, copyBtn)
-
-	return html
+	}
+	return min
+}
+
+// rowStatus deriva el status persistido (ok/mismatch/error) a partir del
+// resultado de la comparación.
+func rowStatus(r ComparisonRow) string {
+	switch {
+	case r.HasError:
+		return jobstore.RowStatusError
+	case r.MatchNombre && r.MatchPaterno && r.MatchMaterno:
+		return jobstore.RowStatusOK
+	default:
+		return jobstore.RowStatusMismatch
+	}
+}
+
+// handleConsultarStream expone el canal de eventos de un job por SSE. Si el
+// cliente envía Last-Event-ID (header o query param), reanuda desde ahí en
+// vez de perder los eventos emitidos mientras estuvo desconectado.
+func handleConsultarStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := r.URL.Query().Get("job_id")
+	if jobID == "" {
+		http.Error(w, "Falta job_id", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming no soportado", http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastEventID, _ = strconv.ParseInt(v, 10, 64)
+	} else if v := r.URL.Query().Get("last_event_id"); v != "" {
+		lastEventID, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events, unsubscribe := broker.Subscribe(jobID, lastEventID)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(sse.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := ev.WriteTo(w); err != nil {
+				return
+			}
+			flusher.Flush()
+			if ev.Name == "done" {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
 }
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// handleMetrics expone las métricas Prometheus de la app (ver
+// internal/metrics). No requiere autenticación para que un scraper externo
+// pueda leerlo sin el token de la API RENIEC.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	promhttp.Handler().ServeHTTP(w, r)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"proyecto/internal/config"
+	"proyecto/internal/provider"
+	"proyecto/internal/ratelimit"
+)
+
+// ProvidersConfigFile reemplaza a TokenFile como fuente de verdad para
+// credenciales: cada proveedor (Decolecta, ApisPeru, ApisNet, ...) trae su
+// propio token y límites de tasa.
+const ProvidersConfigFile = "providers.yaml"
+
+// setupProvider arma el Provider que usará toda la aplicación: una Chain
+// con failover sobre los proveedores de providers.yaml, envuelta en un
+// cache on-disk. Si providers.yaml no existe, cae de vuelta al modo
+// legado (un único proveedor Decolecta leyendo token.txt) para no romper
+// despliegues que todavía no migraron.
+func setupProvider() (provider.Provider, error) {
+	cfg, err := config.Load(ProvidersConfigFile)
+	if err != nil {
+		token := getToken()
+		if token == "" {
+			return nil, fmt.Errorf("no hay %s ni %s: configura al menos uno", ProvidersConfigFile, TokenFile)
+		}
+		return provider.NewDecolecta(ApiBaseURL, token), nil
+	}
+
+	chainProviders := make([]provider.Provider, 0, len(cfg.Providers))
+	for _, pc := range cfg.Providers {
+		p, err := buildProvider(pc)
+		if err != nil {
+			return nil, err
+		}
+		chainProviders = append(chainProviders, p)
+	}
+	chain := provider.NewChain(chainProviders...)
+
+	cache, err := provider.NewCache(cfg.Cache.Path, cfg.Cache.TTL, cfg.Cache.MaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("inicializando cache de proveedores: %w", err)
+	}
+	return provider.WithCache(chain, cache), nil
+}
+
+func buildProvider(pc config.ProviderConfig) (provider.Provider, error) {
+	var p provider.Provider
+	switch pc.Type {
+	case "decolecta":
+		p = provider.NewDecolecta(pc.BaseURL, pc.Token)
+	case "apisperu":
+		p = provider.NewApisPeru(pc.BaseURL, pc.Token)
+	case "apisnet":
+		p = provider.NewApisNet(pc.BaseURL, pc.Token)
+	default:
+		return nil, fmt.Errorf("providers.yaml: tipo de proveedor desconocido %q (proveedor %q)", pc.Type, pc.Name)
+	}
+	return provider.WithRateLimit(p, ratelimit.New(pc.RPS, pc.Burst)), nil
+}